@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer"
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/statusbar"
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/viewer"
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/table"
+	"github.com/brunoluiz/crossplane-explorer/internal/bundle"
+	"github.com/brunoluiz/crossplane-explorer/internal/config"
+	"github.com/brunoluiz/crossplane-explorer/internal/terminal"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdCompare() *cli.Command {
+	return &cli.Command{
+		Usage: `Compare two traces side by side, such as the same claim in staging and production
+Each trace can be a resource name (optionally prefixed by its context), a trace JSON file, a support bundle or a URL serving it
+1. To compare a claim in two contexts, do 'crossplane-explorer compare staging:claim.example.org/my-claim production:claim.example.org/my-claim'
+2. To compare a trace before and after a change, do 'crossplane-explorer compare before.json claim.example.org/my-claim'
+
+Resources are matched by kind and composition resource name. Missing, extra and
+resources with a different status are highlighted, and the describe pane has a diff tab.`,
+		Name:      "compare",
+		ArgsUsage: "<left> <right>",
+		Flags: append(
+			// Traces sources are given as arguments instead
+			lo.Filter(tracerFlags(), func(f cli.Flag, _ int) bool {
+				return !lo.Contains([]string{"stdin", "file", "url", "bundle"}, f.Names()[0])
+			}),
+			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file path, used for custom actions", Value: config.DefaultPath()},
+			&cli.StringFlag{Name: "log", Aliases: []string{"l"}, Usage: "Log destination", Value: "crossplane-explorer.compare.log"},
+			&cli.StringFlag{Name: "events-cmd", Usage: "Which binary should it use to fetch resource events as JSON", Value: "kubectl get events -o json"},
+			&cli.StringSliceFlag{Name: "strip-field", Usage: "Field paths hidden from the describe YAML and diff (toggle with 'c')", Value: xplane.DefaultNoiseFields},
+			&cli.BoolFlag{Name: "redact", Usage: "Redact sensitive data from yanks, bundles and reports (overrides the config)"},
+			&cli.StringFlag{Name: "left-label", Usage: "Name of the left trace, defaults to its argument"},
+			&cli.StringFlag{Name: "right-label", Usage: "Name of the right trace, defaults to its argument"},
+			&cli.BoolFlag{Name: "watch", Aliases: []string{"w"}, Usage: "Refresh both traces periodically"},
+			&cli.DurationFlag{Name: "watch-interval", Aliases: []string{"wi"}, Usage: "Refresh interval for the watcher feature", Value: 5 * time.Second},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.NArg() != 2 {
+				return errors.New("compare requires two traces, such as 'crossplane-explorer compare <left> <right>'")
+			}
+			leftSpec, rightSpec := c.Args().Get(0), c.Args().Get(1)
+
+			cfg, err := config.Load(c.String("config"))
+			if err != nil {
+				return err
+			}
+			if err := cfg.CheckReservedKeys(explorer.IsReservedKey); err != nil {
+				return err
+			}
+
+			redactor, err := getRedactor(c, cfg)
+			if err != nil {
+				return err
+			}
+
+			// Events and actions run against the cluster of the left trace
+			left, env, cleanupLeft, err := getCompareTracer(c, leftSpec)
+			if err != nil {
+				return fmt.Errorf("left trace: %w", err)
+			}
+			defer cleanupLeft()
+
+			right, _, cleanupRight, err := getCompareTracer(c, rightSpec)
+			if err != nil {
+				return fmt.Errorf("right trace: %w", err)
+			}
+			defer cleanupRight()
+
+			// Traces from files or URLs have no cluster to fetch events from
+			var events explorer.EventsGetter
+			switch t := left.(type) {
+			case *bundle.Bundle:
+				events = t
+			case *xplane.CLITraceQuerier:
+				events, err = xplane.NewCLIEventsQuerier(
+					c.String("events-cmd"),
+					xplane.WithEnv(env),
+					xplane.WithTimeout(c.Duration("timeout")),
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			leftLabel := lo.CoalesceOrEmpty(c.String("left-label"), leftSpec)
+			rightLabel := lo.CoalesceOrEmpty(c.String("right-label"), rightSpec)
+
+			f, err := os.Create(c.String("log"))
+			if err != nil {
+				return err
+			}
+
+			// OSC52 yanks are written along with rendered frames
+			out := terminal.NewOutput(os.Stdout)
+			app := tea.NewProgram(
+				explorer.New(
+					slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{})),
+					newTree(
+						table.Column{Title: explorer.HeaderKeyObject, Width: 50},
+						table.Column{Title: explorer.HeaderKeyGroup, Width: 25},
+						table.Column{Title: explorer.HeaderKeyLeft, Width: 35},
+						table.Column{Title: explorer.HeaderKeyLeftSynced, Width: 8},
+						table.Column{Title: explorer.HeaderKeyLeftReady, Width: 7},
+						table.Column{Title: explorer.HeaderKeyRight, Width: 35},
+						table.Column{Title: explorer.HeaderKeyRightSynced, Width: 8},
+						table.Column{Title: explorer.HeaderKeyRightReady, Width: 7},
+						table.Column{Title: explorer.HeaderKeyDiff, Width: 7},
+					),
+					viewer.New(
+						viewer.WithYAMLFilters(xplane.NewFieldStripper(c.StringSlice("strip-field"))),
+						viewer.WithDiffLabels(leftLabel, rightLabel),
+					),
+					statusbar.New(
+						statusbar.WithComparison(leftLabel, rightLabel),
+						statusbar.WithRedaction(redactor.Enabled()),
+					),
+					left,
+					explorer.WithContext(ctx),
+					explorer.WithCompare(right),
+					explorer.WithEventsGetter(events),
+					explorer.WithActions(cfg.Actions),
+					explorer.WithCommandEnv(env),
+					explorer.WithRedactor(redactor),
+					explorer.WithTerminal(out),
+					explorer.WithWatch(c.Bool("watch")),
+					explorer.WithWatchInterval(c.Duration("watch-interval")),
+				),
+				tea.WithAltScreen(),
+				tea.WithContext(ctx),
+				tea.WithOutput(out),
+			)
+
+			_, err = app.Run()
+			return err
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/config"
+	"github.com/brunoluiz/crossplane-explorer/internal/kube"
+	"github.com/brunoluiz/crossplane-explorer/internal/server"
+	"github.com/samber/lo"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdServe() *cli.Command {
+	return &cli.Command{
+		Usage: `Serve tracing from Crossplane over HTTP, with a web page and a JSON API
+1. To serve a live resource using the crossplane CLI, do 'crossplane-explorer serve <object name>'
+2. To serve a trace JSON file kept up to date by another process, do 'crossplane-explorer serve --file trace.json'
+3. To serve a trace JSON fetched from a remote endpoint, do 'crossplane-explorer serve --url https://example.com/trace.json'
+
+The trace is available at /api/trace, and its updates are streamed as server-sent events at /api/stream`,
+		Name: "serve",
+		Flags: append(
+			// Traces piped into stdin never change, so there is nothing to refresh
+			lo.Filter(tracerFlags(), func(f cli.Flag, _ int) bool { return f.Names()[0] != "stdin" }),
+			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file path, used for redaction", Value: config.DefaultPath()},
+			&cli.BoolFlag{Name: "redact", Usage: "Redact sensitive data from the served traces (overrides the config)"},
+			&cli.StringFlag{Name: "addr", Usage: "Address to listen on", Value: "localhost:8080"},
+			&cli.DurationFlag{Name: "watch-interval", Aliases: []string{"wi"}, Usage: "Refresh interval of the trace", Value: 5 * time.Second},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cfg, err := config.Load(c.String("config"))
+			if err != nil {
+				return err
+			}
+			redactor, err := getRedactor(c, cfg)
+			if err != nil {
+				return err
+			}
+
+			kctx := kube.Context{Kubeconfig: c.String("kubeconfig"), Name: c.String("context")}
+			env, cleanup, err := kctx.Env()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			tracer, err := getTracer(c, env)
+			if err != nil {
+				return err
+			}
+
+			logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{}))
+			s := server.New(
+				logger,
+				tracer,
+				server.WithInterval(c.Duration("watch-interval")),
+				server.WithRedactor(redactor),
+			)
+			go s.Run(ctx)
+
+			srv := &http.Server{
+				Addr:              c.String("addr"),
+				Handler:           s.Handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = srv.Shutdown(shutdownCtx)
+			}()
+
+			logger.Info("serving trace", "addr", "http://"+srv.Addr)
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+}
@@ -2,6 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"time"
@@ -11,6 +15,12 @@ import (
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/viewer"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/table"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/tree"
+	"github.com/brunoluiz/crossplane-explorer/internal/bundle"
+	"github.com/brunoluiz/crossplane-explorer/internal/config"
+	"github.com/brunoluiz/crossplane-explorer/internal/kube"
+	"github.com/brunoluiz/crossplane-explorer/internal/notify"
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
+	"github.com/brunoluiz/crossplane-explorer/internal/terminal"
 	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -23,19 +33,123 @@ func cmdTrace() *cli.Command {
 		Usage: `Explore tracing from Crossplane. Usage is available through arguments or data stream
 1. To load it straight from a live resource using the crossplane CLI, do 'crossplane-explorer trace <object name>'
 2. To load it from a trace JSON file, do 'crossplane beta trace -o json <> | crossplane-explorer trace --stdin'
+3. To load it from a trace JSON file kept up to date by another process, do 'crossplane-explorer trace --file trace.json'
+4. To load it from a remote endpoint serving the trace JSON, do 'crossplane-explorer trace --url https://example.com/trace.json'
+5. To explore a support bundle offline, do 'crossplane-explorer trace --bundle bundle.tar.gz'
 
-Live mode is only available for (1) through the use of --watch / --watch-interval (see flag usage below)`,
+Live mode is only available for (1), (3) and (4) through the use of --watch / --watch-interval (see flag usage below)`,
 		Name:    "trace",
 		Aliases: []string{"t"},
-		Flags: []cli.Flag{
+		Flags: append(tracerFlags(),
+			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file path, used for custom actions", Value: config.DefaultPath()},
 			&cli.StringFlag{Name: "log", Aliases: []string{"l"}, Usage: "Log destination", Value: "crossplane-explorer.trace.log"},
-			&cli.StringFlag{Name: "cmd", Usage: "Which binary should it use to generate the JSON trace", Value: "crossplane beta trace -o json"},
-			&cli.StringFlag{Name: "namespace", Aliases: []string{"n", "ns"}, Usage: "Kubernetes namespace to be used"},
-			&cli.BoolFlag{Name: "stdin", Aliases: []string{"in"}, Usage: "Specify in case file is piped into stdin"},
+			&cli.StringFlag{Name: "events-cmd", Usage: "Which binary should it use to fetch resource events as JSON", Value: "kubectl get events -o json"},
+			&cli.StringSliceFlag{Name: "strip-field", Usage: "Field paths hidden from the describe YAML (toggle with 'c')", Value: xplane.DefaultNoiseFields},
 			&cli.BoolFlag{Name: "watch", Aliases: []string{"w"}, Usage: "Refresh trace every 10 seconds"},
+			&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "Print a provisioning report or write a support bundle instead of opening the explorer (report, report-json or bundle)"},
+			&cli.BoolFlag{Name: "redact", Usage: "Redact sensitive data from yanks, bundles and reports (overrides the config)"},
+			&cli.StringFlag{Name: "bundle-dir", Usage: "Where support bundles are written (with 'X')", Value: "."},
+			&cli.BoolFlag{Name: "live", Usage: "Watch the Kubernetes API for changes instead of polling, for traces from the cluster (implies --watch)"},
 			&cli.DurationFlag{Name: "watch-interval", Aliases: []string{"wi"}, Usage: "Refresh interval for the watcher feature", Value: 5 * time.Second},
-		},
+		),
 		Action: func(ctx context.Context, c *cli.Command) error {
+			cfg, err := config.Load(c.String("config"))
+			if err != nil {
+				return err
+			}
+			if err := cfg.CheckReservedKeys(explorer.IsReservedKey); err != nil {
+				return err
+			}
+
+			kctx := kube.Context{Kubeconfig: c.String("kubeconfig"), Name: c.String("context")}
+			env, cleanup, err := kctx.Env()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			tracer, err := getTracer(c, env)
+			if err != nil {
+				return err
+			}
+			// Live updates watch the cluster, which would overwrite traces
+			// coming from anywhere else
+			if _, ok := tracer.(*xplane.CLITraceQuerier); c.Bool("live") && !ok {
+				return errors.New("--live only works with traces from the cluster, not with --stdin, --file, --url or --bundle")
+			}
+
+			redactor, err := getRedactor(c, cfg)
+			if err != nil {
+				return err
+			}
+
+			// Events are only fetched from the cluster the trace came from, so
+			// traces read from stdin, files or URLs have none
+			var events explorer.EventsGetter
+			var kubeContext, kubeCluster string
+			switch t := tracer.(type) {
+			case *bundle.Bundle:
+				// Bundles are explored offline, along with the events in them
+				events = t
+				kubeContext, kubeCluster = t.Manifest.Context, t.Manifest.Cluster
+			case *xplane.CLITraceQuerier:
+				events, err = xplane.NewCLIEventsQuerier(
+					c.String("events-cmd"),
+					xplane.WithEnv(env),
+					xplane.WithTimeout(c.Duration("timeout")),
+				)
+				if err != nil {
+					return err
+				}
+				kubeContext, kubeCluster, _ = kctx.Current()
+			}
+
+			switch output := c.String("output"); output {
+			case "":
+			case "bundle":
+				trace, err := tracer.GetTrace(ctx)
+				if err != nil {
+					return err
+				}
+				manifest := bundle.Manifest{Context: kubeContext, Cluster: kubeCluster, CreatedAt: time.Now()}
+				return bundle.Write(ctx, os.Stdout, trace, events, manifest, bundle.WithRedactor(redactor))
+			default:
+				return printReport(ctx, os.Stdout, tracer, redactor, output)
+			}
+
+			// The bell and OSC52 yanks are written along with rendered frames
+			out := terminal.NewOutput(os.Stdout)
+			notifier, err := notify.New(cfg.Notifications, notify.WithOutput(out))
+			if err != nil {
+				return err
+			}
+
+			opts := []explorer.WithOpt{
+				explorer.WithContext(ctx),
+				explorer.WithEventsGetter(events),
+				explorer.WithActions(cfg.Actions),
+				explorer.WithCommandEnv(env),
+				explorer.WithNotifier(notifier),
+				explorer.WithWatch(c.Bool("watch") || c.Bool("live") || c.IsSet("file")),
+				explorer.WithWatchInterval(c.Duration("watch-interval")),
+				explorer.WithBundleDir(c.String("bundle-dir")),
+				explorer.WithKubeContext(kubeContext, kubeCluster),
+				explorer.WithRedactor(redactor),
+				explorer.WithTerminal(out),
+			}
+			if c.Bool("live") {
+				w, err := getLiveWatcher(kctx)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, explorer.WithLiveWatcher(w))
+			}
+
+			statusbarOpts := []statusbar.WithOpt{statusbar.WithRedaction(redactor.Enabled())}
+			if kubeContext != "" {
+				statusbarOpts = append(statusbarOpts, statusbar.WithKubeContext(kubeContext, kubeCluster))
+			}
+
 			f, err := os.Create(c.String("log"))
 			if err != nil {
 				return err
@@ -44,33 +158,25 @@ Live mode is only available for (1) through the use of --watch / --watch-interva
 			app := tea.NewProgram(
 				explorer.New(
 					slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{})),
-					tree.New(table.New(
-						table.WithColumns([]table.Column{
-							{Title: explorer.HeaderKeyObject, Width: 60},
-							{Title: explorer.HeaderKeyGroup, Width: 30},
-							{Title: explorer.HeaderKeySynced, Width: 7},
-							{Title: explorer.HeaderKeySyncedLast, Width: 19},
-							{Title: explorer.HeaderKeyReady, Width: 7},
-							{Title: explorer.HeaderKeyReadyLast, Width: 19},
-							{Title: explorer.HeaderKeyStatus, Width: 68},
-						}),
-						table.WithFocused(true),
-						table.WithStyles(func() table.Styles {
-							s := table.DefaultStyles()
-							s.Selected = lipgloss.NewStyle().
-								Foreground(lipgloss.ANSIColor(ansi.Black)).
-								Background(lipgloss.ANSIColor(ansi.White))
-							return s
-						}()),
-					)),
-					viewer.New(),
-					statusbar.New(),
-					getTracer(c),
-					explorer.WithWatch(c.Bool("watch")),
-					explorer.WithWatchInterval(c.Duration("watch-interval")),
+					newTree(
+						table.Column{Title: explorer.HeaderKeyObject, Width: 60},
+						table.Column{Title: explorer.HeaderKeyGroup, Width: 30},
+						table.Column{Title: explorer.HeaderKeySynced, Width: 7},
+						table.Column{Title: explorer.HeaderKeySyncedLast, Width: 19},
+						table.Column{Title: explorer.HeaderKeyReady, Width: 7},
+						table.Column{Title: explorer.HeaderKeyReadyLast, Width: 19},
+						table.Column{Title: explorer.HeaderKeyStatus, Width: 68},
+					),
+					viewer.New(
+						viewer.WithYAMLFilters(xplane.NewFieldStripper(c.StringSlice("strip-field"))),
+					),
+					statusbar.New(statusbarOpts...),
+					tracer,
+					opts...,
 				),
 				tea.WithAltScreen(),
 				tea.WithContext(ctx),
+				tea.WithOutput(out),
 			)
 
 			_, err = app.Run()
@@ -79,14 +185,52 @@ Live mode is only available for (1) through the use of --watch / --watch-interva
 	}
 }
 
-func getTracer(c *cli.Command) explorer.Tracer {
-	if c.Bool("stdin") {
-		return xplane.NewReaderTraceQuerier(os.Stdin)
+func newTree(columns ...table.Column) tree.Model {
+	return tree.New(table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithStyles(func() table.Styles {
+			s := table.DefaultStyles()
+			s.Selected = lipgloss.NewStyle().
+				Foreground(lipgloss.ANSIColor(ansi.Black)).
+				Background(lipgloss.ANSIColor(ansi.White))
+			return s
+		}()),
+	))
+}
+
+// getRedactor returns the configured redactor, which can be toggled by flag
+func getRedactor(c *cli.Command, cfg config.Config) (*redact.Redactor, error) {
+	if c.IsSet("redact") {
+		cfg.Redaction.Enabled = c.Bool("redact")
+	}
+	return redact.New(cfg.Redaction)
+}
+
+func getLiveWatcher(kctx kube.Context) (*kube.Watcher, error) {
+	cfg, err := kctx.RESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("live updates require access to the cluster: %w", err)
 	}
+	return kube.NewWatcherForConfig(cfg)
+}
 
-	return xplane.NewCLITraceQuerier(
-		c.String("cmd"),
-		c.String("namespace"),
-		c.Args().First(),
-	)
+func printReport(ctx context.Context, w io.Writer, tracer explorer.Tracer, redactor *redact.Redactor, output string) error {
+	trace, err := tracer.GetTrace(ctx)
+	if err != nil {
+		return err
+	}
+	report := xplane.NewReport(redactor.Resource(trace), time.Now())
+
+	switch output {
+	case "report":
+		_, err = fmt.Fprintln(w, report)
+		return err
+	case "report-json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	default:
+		return fmt.Errorf("unknown output %q, must be report, report-json or bundle", output)
+	}
 }
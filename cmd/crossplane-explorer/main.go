@@ -24,6 +24,8 @@ func main() {
 
 	if err := cmdMain(
 		cmdTrace(),
+		cmdServe(),
+		cmdCompare(),
 	).Run(ctx, os.Args); err != nil {
 		log.Println(err)
 	}
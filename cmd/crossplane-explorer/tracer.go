@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer"
+	"github.com/brunoluiz/crossplane-explorer/internal/bundle"
+	"github.com/brunoluiz/crossplane-explorer/internal/kube"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/urfave/cli/v3"
+)
+
+// tracerFlags select where traces come from, shared by all commands
+func tracerFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "cmd", Usage: "Which binary should it use to generate the JSON trace", Value: "crossplane beta trace -o json"},
+		&cli.DurationFlag{Name: "timeout", Usage: "Maximum time each trace or events command can take", Value: 30 * time.Second},
+		&cli.StringFlag{Name: "kubeconfig", Usage: "Path to the kubeconfig file, used by the tracer, events, actions and live updates"},
+		&cli.StringFlag{Name: "context", Usage: "Kubernetes context to be used, instead of the current one"},
+		&cli.StringFlag{Name: "namespace", Aliases: []string{"n", "ns"}, Usage: "Kubernetes namespace to be used"},
+		&cli.BoolFlag{Name: "stdin", Aliases: []string{"in"}, Usage: "Specify in case file is piped into stdin"},
+		&cli.StringFlag{Name: "file", Aliases: []string{"f"}, Usage: "Trace JSON file, reloaded whenever it changes (implies --watch)"},
+		&cli.StringFlag{Name: "url", Usage: "URL serving the trace JSON, instead of running the crossplane CLI"},
+		&cli.StringSliceFlag{Name: "url-header", Usage: "Header sent to --url, as 'Name: value' (environment variables such as $TOKEN are expanded)"},
+		&cli.StringFlag{Name: "url-token-env", Usage: "Environment variable holding a bearer token sent to --url", Value: "CROSSPLANE_EXPLORER_TOKEN"},
+		&cli.StringFlag{Name: "url-ca", Usage: "PEM file with certificate authorities trusted by --url"},
+		&cli.StringFlag{Name: "url-cert", Usage: "Client certificate PEM file sent to --url"},
+		&cli.StringFlag{Name: "url-key", Usage: "Client key PEM file for --url-cert"},
+		&cli.BoolFlag{Name: "url-insecure", Usage: "Skip verifying the certificate of --url"},
+		&cli.StringFlag{Name: "bundle", Usage: "Support bundle to explore offline, with the events in it"},
+	}
+}
+
+func getTracer(c *cli.Command, env []string) (explorer.Tracer, error) {
+	if c.Bool("stdin") {
+		return xplane.NewReaderTraceQuerier(os.Stdin), nil
+	}
+	if f := c.String("file"); f != "" {
+		return xplane.NewFileTraceQuerier(f), nil
+	}
+	if u := c.String("url"); u != "" {
+		return getHTTPTracer(c, u)
+	}
+	if b := c.String("bundle"); b != "" {
+		return bundle.Open(b)
+	}
+
+	return getCLITracer(c, c.Args().First(), env)
+}
+
+func getCLITracer(c *cli.Command, name string, env []string) (explorer.Tracer, error) {
+	return xplane.NewCLITraceQuerier(
+		c.String("cmd"),
+		c.String("namespace"),
+		name,
+		xplane.WithEnv(env),
+		xplane.WithTimeout(c.Duration("timeout")),
+	)
+}
+
+// getCompareTracer returns the tracer of one side of a comparison, given as a
+// URL, a trace JSON file, a support bundle or a resource name, along with the environment used
+// to reach its cluster. Resource names can be prefixed by the context they
+// are in, such as staging:claim.example.org/my-claim.
+func getCompareTracer(c *cli.Command, spec string) (explorer.Tracer, []string, func(), error) {
+	isURL := strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://")
+	isFile := strings.HasSuffix(spec, ".json")
+	isBundle := strings.HasSuffix(spec, ".tar.gz") || strings.HasSuffix(spec, ".tgz")
+
+	kctx := kube.Context{Kubeconfig: c.String("kubeconfig"), Name: c.String("context")}
+	name := spec
+	// Resource names can't have colons, unlike some contexts (such as EKS ARNs)
+	if i := strings.LastIndex(spec, ":"); i >= 0 && !isURL && !isFile && !isBundle {
+		kctx.Name, name = spec[:i], spec[i+1:]
+	}
+
+	env, cleanup, err := kctx.Env()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var t explorer.Tracer
+	switch {
+	case isURL:
+		t, err = getHTTPTracer(c, spec)
+	case isFile:
+		t = xplane.NewFileTraceQuerier(spec)
+	case isBundle:
+		t, err = bundle.Open(spec)
+	default:
+		t, err = getCLITracer(c, name, env)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	return t, env, cleanup, nil
+}
+
+func getHTTPTracer(c *cli.Command, url string) (explorer.Tracer, error) {
+	opts := []xplane.HTTPOpt{
+		xplane.WithHTTPTimeout(c.Duration("timeout")),
+		xplane.WithBearerToken(os.Getenv(c.String("url-token-env"))),
+		xplane.WithCA(c.String("url-ca")),
+		xplane.WithClientCert(c.String("url-cert"), c.String("url-key")),
+		xplane.WithInsecureSkipVerify(c.Bool("url-insecure")),
+	}
+	for _, h := range c.StringSlice("url-header") {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: value'", h)
+		}
+		opts = append(opts, xplane.WithHeader(strings.TrimSpace(name), strings.TrimSpace(os.ExpandEnv(value))))
+	}
+
+	return xplane.NewHTTPTraceQuerier(url, opts...)
+}
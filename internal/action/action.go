@@ -0,0 +1,113 @@
+package action
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"text/template"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+type Mode string
+
+const (
+	// ModePane runs the command in the background and shows its output in a pane
+	ModePane Mode = "pane"
+	// ModeExec suspends the explorer and hands the terminal over to the command
+	ModeExec Mode = "exec"
+)
+
+// Action is a user-defined shell command, rendered as a Go template over the
+// selected resource and bound to a key in the tree
+type Action struct {
+	Name    string `yaml:"name"`
+	Key     string `yaml:"key"`
+	Command string `yaml:"command"`
+	Mode    Mode   `yaml:"mode"`
+
+	tmpl *template.Template
+}
+
+// Data is available within action templates as `.`, for example
+// `{{ .Name }}`, `{{ index .Annotations "crossplane.io/external-name" }}` or
+// `{{ .Field "status.atProvider.arn" }}`
+type Data struct {
+	Kind        string
+	Group       string
+	Version     string
+	APIVersion  string
+	Name        string
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	Object      map[string]any
+}
+
+// Field returns the value at a crossplane field path (e.g. `spec.forProvider.region`)
+func (d Data) Field(path string) (any, error) {
+	return fieldpath.Pave(d.Object).GetValue(path)
+}
+
+// Validate checks the action is well defined, parsing its command template
+func (a *Action) Validate() error {
+	if a.Name == "" {
+		return errors.New("action name is required")
+	}
+	if a.Key == "" {
+		return fmt.Errorf("action %q: key is required", a.Name)
+	}
+	if a.Mode == "" {
+		a.Mode = ModePane
+	}
+	if a.Mode != ModePane && a.Mode != ModeExec {
+		return fmt.Errorf("action %q: unknown mode %q", a.Name, a.Mode)
+	}
+
+	tmpl, err := Parse(a.Name, a.Command)
+	if err != nil {
+		return fmt.Errorf("action %q: %w", a.Name, err)
+	}
+	a.tmpl = tmpl
+
+	return nil
+}
+
+// Render returns the shell command for the given resource
+func (a Action) Render(r *xplane.Resource) (string, error) {
+	if a.tmpl == nil {
+		return "", fmt.Errorf("action %q: not validated", a.Name)
+	}
+
+	gvk := r.Unstructured.GroupVersionKind()
+	data := Data{
+		Kind:        gvk.Kind,
+		Group:       gvk.Group,
+		Version:     gvk.Version,
+		APIVersion:  r.Unstructured.GetAPIVersion(),
+		Name:        r.Unstructured.GetName(),
+		Namespace:   r.Unstructured.GetNamespace(),
+		Annotations: r.Unstructured.GetAnnotations(),
+		Labels:      r.Unstructured.GetLabels(),
+		Object:      r.Unstructured.Object,
+	}
+
+	var b bytes.Buffer
+	if err := a.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("action %q: %w", a.Name, err)
+	}
+	return b.String(), nil
+}
+
+// Cmd returns the command to be executed for the given resource
+func (a Action) Cmd(r *xplane.Resource) (*exec.Cmd, error) {
+	cmd, err := a.Render(r)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint // commands are defined by the user
+	return exec.Command("sh", "-c", cmd), nil
+}
@@ -0,0 +1,169 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResource(name string) *xplane.Resource {
+	return &xplane.Resource{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata": map[string]any{
+			"name":        name,
+			"annotations": map[string]any{"crossplane.io/external-name": "external"},
+		},
+		"spec": map[string]any{"forProvider": map[string]any{"region": "eu-west-1"}},
+	}}}
+}
+
+func TestValidate(t *testing.T) {
+	type args struct {
+		action Action
+	}
+
+	type want struct {
+		err  bool
+		mode Mode
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Valid": {
+			reason: "Should default to the pane mode",
+			args:   args{action: Action{Name: "logs", Key: "L", Command: "echo {{ .Name }}"}},
+			want:   want{mode: ModePane},
+		},
+		"MissingName": {
+			reason: "Should require a name",
+			args:   args{action: Action{Key: "L", Command: "echo"}},
+			want:   want{err: true},
+		},
+		"MissingKey": {
+			reason: "Should require a key",
+			args:   args{action: Action{Name: "logs", Command: "echo"}},
+			want:   want{err: true},
+		},
+		"UnknownMode": {
+			reason: "Should reject unknown modes",
+			args:   args{action: Action{Name: "logs", Key: "L", Command: "echo", Mode: "window"}},
+			want:   want{err: true},
+		},
+		"InvalidTemplate": {
+			reason: "Should reject commands which are not valid templates",
+			args:   args{action: Action{Name: "logs", Key: "L", Command: "echo {{ .Name "}},
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := tc.args.action
+			err := a.Validate()
+			if (err != nil) != tc.want.err {
+				t.Fatalf("%s\nValidate() error = %v, want error %v", tc.reason, err, tc.want.err)
+			}
+			if err == nil && a.Mode != tc.want.mode {
+				t.Errorf("%s\nValidate() mode = %q, want %q", tc.reason, a.Mode, tc.want.mode)
+			}
+		})
+	}
+}
+
+func TestCmd(t *testing.T) {
+	type args struct {
+		command string
+		name    string
+	}
+
+	type want struct {
+		output string
+		err    bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Fields": {
+			reason: "Should render resource fields, annotations and field paths",
+			args: args{
+				command: `printf %s {{ .Kind }}.{{ .Group }}/{{ .Name }},{{ index .Annotations "crossplane.io/external-name" }},{{ .Field "spec.forProvider.region" }}`,
+				name:    "bucket",
+			},
+			want: want{output: "Bucket.s3.aws.upbound.io/bucket,external,eu-west-1"},
+		},
+		"QuoteSingleQuote": {
+			reason: "Should quote names containing single quotes",
+			args:   args{command: "printf %s {{ quote .Name }}", name: "it's"},
+			want:   want{output: "it's"},
+		},
+		"QuoteSubstitution": {
+			reason: "Should not run command substitutions within quoted names",
+			args:   args{command: "printf %s {{ quote .Name }}", name: "$(echo pwned)`echo pwned`"},
+			want:   want{output: "$(echo pwned)`echo pwned`"},
+		},
+		"QuoteSpaces": {
+			reason: "Should keep quoted names with spaces as a single argument",
+			args:   args{command: "printf '[%s]' {{ quote .Name }}", name: "my  bucket"},
+			want:   want{output: "[my  bucket]"},
+		},
+		"EscapeSubstitution": {
+			reason: "Should not run command substitutions within names, even if not quoted",
+			args:   args{command: "printf %s {{ .Name }}", name: "$(echo pwned)`echo pwned`;echo pwned"},
+			want:   want{output: "$(echo pwned)`echo pwned`;echo pwned"},
+		},
+		"EscapeSpaces": {
+			reason: "Should keep names with spaces as a single argument, even if not quoted",
+			args:   args{command: "printf '[%s]' {{ .Name }}", name: "my  bucket"},
+			want:   want{output: "[my  bucket]"},
+		},
+		"EscapeWithinBlocks": {
+			reason: "Should escape values printed within conditions",
+			args:   args{command: "printf %s {{ if .Name }}{{ .Name }}{{ end }}{{ with .Namespace }}{{ . }}{{ else }}{{ .Name }}{{ end }}", name: "a b"},
+			want:   want{output: "a ba b"},
+		},
+		"Raw": {
+			reason: "Should insert raw values as is",
+			args:   args{command: "printf %s {{ raw .Name }}", name: "a b"},
+			want:   want{output: "ab"},
+		},
+		"UnknownField": {
+			reason: "Should fail for fields which do not exist",
+			args:   args{command: "echo {{ .Nope }}", name: "bucket"},
+			want:   want{err: true},
+		},
+		"MissingFieldPath": {
+			reason: "Should fail for field paths which are not set",
+			args:   args{command: `echo {{ .Field "status.atProvider.arn" }}`, name: "bucket"},
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := Action{Name: name, Key: "L", Command: tc.args.command}
+			if err := a.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd, err := a.Cmd(newResource(tc.args.name))
+			if (err != nil) != tc.want.err {
+				t.Fatalf("%s\nCmd() error = %v, want error %v", tc.reason, err, tc.want.err)
+			}
+			if err != nil {
+				return
+			}
+
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("%s\n%s failed: %v", tc.reason, cmd, err)
+			}
+			if string(out) != tc.want.output {
+				t.Errorf("%s\nCmd() output = %q, want %q", tc.reason, out, tc.want.output)
+			}
+		})
+	}
+}
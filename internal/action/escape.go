@@ -0,0 +1,96 @@
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// escapeFunc is appended to every pipeline printing a value
+const escapeFunc = "_shell_escape"
+
+// shellSafe matches values which do not need quoting
+var shellSafe = regexp.MustCompile(`^[\w@%+=:,./-]+$`)
+
+// shellString is already safe to be used in a shell command
+type shellString string
+
+// Funcs are the helpers available within command templates
+var Funcs = template.FuncMap{
+	// quote wraps a value in single quotes, making it safe to use as a shell argument
+	"quote": func(v any) shellString {
+		return shellString(quote(fmt.Sprint(v)))
+	},
+	// raw inserts a value as is, without escaping it
+	"raw": func(v any) shellString {
+		return shellString(fmt.Sprint(v))
+	},
+	escapeFunc: escape,
+}
+
+// Parse parses a command template. Values are shell-escaped, as they usually
+// come from the cluster, unless they go through raw (or quote) first.
+func Parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(Funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			escapeNode(t.Tree.Root)
+		}
+	}
+	return tmpl, nil
+}
+
+// escapeNode pipes every printed value into the escape function, the same way
+// html/template does with its escapers
+func escapeNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			escapeNode(c)
+		}
+	case *parse.ActionNode:
+		// Variable declarations don't print anything
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(escapeFunc).SetTree(nil).SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		escapeNode(n.List)
+		escapeNode(n.ElseList)
+	case *parse.RangeNode:
+		escapeNode(n.List)
+		escapeNode(n.ElseList)
+	case *parse.WithNode:
+		escapeNode(n.List)
+		escapeNode(n.ElseList)
+	}
+}
+
+func escape(v any) string {
+	if s, ok := v.(shellString); ok {
+		return string(s)
+	}
+
+	s := fmt.Sprint(v)
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return quote(s)
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
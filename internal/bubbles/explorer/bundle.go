@@ -0,0 +1,54 @@
+package explorer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/statusbar"
+	"github.com/brunoluiz/crossplane-explorer/internal/bundle"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithBundleDir sets where support bundles are written, defaulting to the
+// working directory
+func WithBundleDir(dir string) func(*Model) {
+	return func(m *Model) {
+		m.bundleDir = dir
+	}
+}
+
+// WithKubeContext records which context and cluster traces come from, so it
+// can be included in support bundles
+func WithKubeContext(context, cluster string) func(*Model) {
+	return func(m *Model) {
+		m.kubeContext = context
+		m.kubeCluster = cluster
+	}
+}
+
+// writeBundle writes the current trace, along with the events of all its
+// objects, into a support bundle
+func (m *Model) writeBundle() tea.Cmd {
+	if m.trace == nil {
+		return nil
+	}
+
+	trace := m.trace
+	now := time.Now()
+	name := filepath.Join(m.bundleDir, fmt.Sprintf(
+		"crossplane-explorer-%s-%s-%s.tar.gz",
+		strings.ToLower(trace.Unstructured.GetKind()),
+		m.redactor.String(trace.Unstructured.GetName()),
+		now.Format("20060102-150405"),
+	))
+	manifest := bundle.Manifest{Context: m.kubeContext, Cluster: m.kubeCluster, CreatedAt: now}
+
+	return func() tea.Msg {
+		if err := bundle.WriteFile(m.ctx, name, trace, m.events, manifest, bundle.WithRedactor(m.redactor)); err != nil {
+			return statusbar.Error("bundle failed: %s", err)
+		}
+		return statusbar.Info("bundle written to %s", name)
+	}
+}
@@ -0,0 +1,116 @@
+package explorer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/tree"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	HeaderKeyLeft        = "LEFT"
+	HeaderKeyLeftSynced  = "L.SYNCED"
+	HeaderKeyLeftReady   = "L.READY"
+	HeaderKeyRight       = "RIGHT"
+	HeaderKeyRightSynced = "R.SYNCED"
+	HeaderKeyRightReady  = "R.READY"
+	HeaderKeyDiff        = "DIFF"
+)
+
+// comparedMsg carries both traces being compared
+type comparedMsg struct {
+	left  *xplane.Resource
+	right *xplane.Resource
+}
+
+// WithCompare compares the trace (left) with the one from another tracer
+// (right). Live updates are not supported while comparing.
+func WithCompare(t Tracer) func(*Model) {
+	return func(m *Model) {
+		m.compare = t
+	}
+}
+
+// getComparison fetches both traces at once
+func (m Model) getComparison() tea.Cmd {
+	return func() tea.Msg {
+		var msg comparedMsg
+		var leftErr, rightErr error
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			msg.left, leftErr = m.tracer.GetTrace(m.ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			msg.right, rightErr = m.compare.GetTrace(m.ctx)
+			if rightErr != nil {
+				rightErr = fmt.Errorf("compared trace: %w", rightErr)
+			}
+		}()
+		wg.Wait()
+
+		if err := errors.Join(leftErr, rightErr); err != nil {
+			return traceErrMsg{err: err}
+		}
+		return msg
+	}
+}
+
+func (m *Model) onCompared(msg comparedMsg) tea.Cmd {
+	if msg.left == nil || msg.right == nil {
+		return m.onTraceErr(errors.New("trace is empty"))
+	}
+
+	m.comparison = xplane.Compare(msg.left, msg.right)
+	return m.onLoad(msg.left)
+}
+
+func addComparisonNodes(
+	c *xplane.Comparison,
+	n *tree.Node,
+	resByNode map[*tree.Node]*xplane.Resource,
+	comparisons map[*tree.Node]*xplane.Comparison,
+) {
+	addNode(c.Resource(), n, resByNode)
+	comparisons[n] = c
+
+	n.Details[HeaderKeyLeft], n.Details[HeaderKeyLeftSynced], n.Details[HeaderKeyLeftReady] = compareColumns(c.Left)
+	n.Details[HeaderKeyRight], n.Details[HeaderKeyRightSynced], n.Details[HeaderKeyRightReady] = compareColumns(c.Right)
+
+	// Colours highlight differences, rather than the health of resources
+	n.Color = nil
+	switch c.Change() {
+	case xplane.ChangeMissing:
+		n.Details[HeaderKeyDiff] = "missing"
+		n.Color = lipgloss.ANSIColor(ansi.Red)
+	case xplane.ChangeExtra:
+		n.Details[HeaderKeyDiff] = "extra"
+		n.Color = lipgloss.ANSIColor(ansi.Green)
+	case xplane.ChangeStatus:
+		n.Details[HeaderKeyDiff] = "status"
+		n.Color = lipgloss.ANSIColor(ansi.Yellow)
+	}
+
+	n.Children = make([]*tree.Node, len(c.Children))
+	for k, cc := range c.Children {
+		n.Children[k] = &tree.Node{}
+		addComparisonNodes(cc, n.Children[k], resByNode, comparisons)
+	}
+}
+
+// compareColumns returns the name, synced and ready columns of one side
+func compareColumns(r *xplane.Resource) (string, string, string) {
+	if r == nil {
+		return "-", "-", "-"
+	}
+	status := xplane.GetResourceStatus(r, r.Unstructured.GetName())
+	return status.Name, status.Synced, status.Ready
+}
@@ -1,16 +1,23 @@
 package explorer
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
-	"github.com/atotto/clipboard"
+	"github.com/brunoluiz/crossplane-explorer/internal/action"
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/statusbar"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/viewer"
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/menu"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/tree"
+	textviewer "github.com/brunoluiz/crossplane-explorer/internal/bubbles/viewer"
+	"github.com/brunoluiz/crossplane-explorer/internal/clipboard"
 	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/samber/lo"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -21,9 +28,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case *xplane.Resource:
 		cmd = m.onLoad(msg)
+	case comparedMsg:
+		cmd = m.onCompared(msg)
+	case traceErrMsg:
+		cmd = m.onTraceErr(msg.err)
+		if m.err != nil {
+			return m, nil
+		}
+	case tickMsg:
+		cmd = m.onTick(msg)
+	case clockMsg:
+		cmd = clock()
+	case liveStartedMsg:
+		cmd = m.onLiveStarted(msg)
+	case liveUpdateMsg:
+		cmd = m.onLiveUpdate(msg)
+	case liveErrMsg:
+		cmd = m.onLiveErr(msg)
+	case statusbar.WatchStatusMsg, statusbar.MessageMsg:
+		// Statusbar messages must not be lost while it is hidden by other panes
+		*m.statusbar, cmd = m.statusbar.Update(msg)
+		return m, cmd
 	case tea.WindowSizeMsg:
 		return m, m.onResize(msg)
+	case menu.SelectedMsg:
+		return m, m.onYank(msg)
+	case statusbar.BreadcrumbSelectedMsg:
+		m.onBreadcrumb(msg)
+		return m, nil
+	case actionOutputMsg:
+		m.onActionOutput(msg)
+		return m, nil
 	case tea.KeyMsg:
+		// An open menu takes over all key presses
+		if m.yank.IsOpen() {
+			m.yank, cmd = m.yank.Update(msg)
+			return m, cmd
+		}
+		// So does the breadcrumb, except for quitting
+		if m.statusbar.IsBrowsing() && msg.String() != "ctrl+c" {
+			*m.statusbar, cmd = m.statusbar.Update(msg)
+			return m, cmd
+		}
 		cmd = m.onKey(msg)
 	}
 
@@ -32,6 +78,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var viewerCmd tea.Cmd
 		m.viewer, viewerCmd = m.viewer.Update(msg)
 		return m, tea.Batch(cmd, viewerCmd)
+	case PaneOutput:
+		var outputCmd tea.Cmd
+		m.output, outputCmd = m.output.Update(msg)
+		return m, tea.Batch(cmd, outputCmd)
 	case PaneTree:
 		var treeCmd, statusCmd tea.Cmd
 		m.tree, treeCmd = m.tree.Update(msg)
@@ -45,25 +95,69 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) onLoad(data *xplane.Resource) tea.Cmd {
 	if data == nil {
-		return nil
+		return m.onTraceErr(errors.New("trace is empty"))
+	}
+
+	cmd := m.setTrace(data)
+	liveCmd := m.startLive()
+	return tea.Batch(cmd, liveCmd, m.onRefreshed(nil))
+}
+
+// setTrace renders a trace, keeping the current selection and description
+func (m *Model) setTrace(data *xplane.Resource) tea.Cmd {
+	parents := map[*xplane.Resource]*xplane.Resource{}
+	addParents(data, parents)
+	if m.comparison != nil {
+		addParents(m.comparison.Right, parents)
+	}
+
+	notifyCmd := m.notify(m.trace, data)
+
+	m.trace = data
+	m.summary = xplane.NewSummary(data)
+	m.parents = parents
+	m.renderNodes()
+	m.loaded = true
+
+	if m.pane == PaneOutput && m.outputView != nil {
+		m.renderView(true)
 	}
 
-	nodes := []*tree.Node{
-		{Key: "root", Children: make([]*tree.Node, 1)},
+	// Keep describing the same resource (or its closest ancestor) with fresh data
+	var eventsCmd tea.Cmd
+	if v := m.resByNode[m.tree.Current()]; m.pane == PaneSummary && v != nil {
+		m.viewer.Refresh(m.describeInput(m.tree.Current()))
+		eventsCmd = m.getEvents(v)
 	}
+
+	return tea.Batch(eventsCmd, notifyCmd)
+}
+
+// renderNodes lays out the trace (or comparison) as a tree or in groups
+func (m *Model) renderNodes() {
+	root := &tree.Node{}
 	resByNode := map[*tree.Node]*xplane.Resource{}
-	addNodes(data, nodes[0], resByNode)
+	comparisons := map[*tree.Node]*xplane.Comparison{}
+	if m.comparison != nil {
+		addComparisonNodes(m.comparison, root, resByNode, comparisons)
+	} else {
+		addNodes(m.trace, root, resByNode)
+	}
+
+	nodes := []*tree.Node{root}
+	if m.groupBy != GroupByNone {
+		nodes = groupNodes(root, resByNode, m.groupBy)
+	}
 
-	m.tree.SetNodes(nodes)
 	m.resByNode = resByNode
+	m.comparisons = comparisons
+	m.tree.SetNodes(nodes)
 
-	if m.watch {
-		return tea.Tick(m.watchInterval, func(_ time.Time) tea.Msg {
-			return m.getTrace()()
-		})
+	m.statusbar.SetNodeCount(len(resByNode))
+	m.statusbar.SetFilter("")
+	if m.groupBy != GroupByNone {
+		m.statusbar.SetFilter("by " + m.groupBy.String())
 	}
-
-	return nil
 }
 
 func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
@@ -71,45 +165,249 @@ func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
 	m.height = msg.Height
 
 	top, right, _, left := lipgloss.NewStyle().Padding(1).GetPadding()
-	m.tree, _ = m.tree.Update(tea.WindowSizeMsg{Width: m.width - right - left, Height: m.height - top})
+	m.tree, _ = m.tree.Update(tea.WindowSizeMsg{Width: m.width - right - left, Height: m.height - top - summaryHeight})
 	*m.statusbar, _ = m.statusbar.Update(msg)
 	m.viewer, _ = m.viewer.Update(msg)
+	m.yank, _ = m.yank.Update(msg)
+	m.output, _ = m.output.Update(msg)
 
 	return nil
 }
 
 func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	// Let viewers consume keys while, for example, a search is being typed
+	capturing := (m.pane == PaneSummary && m.viewer.IsCapturingInput()) ||
+		(m.pane == PaneOutput && m.output.IsCapturingInput())
+	if capturing && msg.String() != "ctrl+c" {
+		return nil
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "ctlr+d":
-		return tea.Interrupt
+		return m.quit()
 	case "y":
-		//nolint // ignore errors
-		clipboard.WriteAll(m.tree.Current().Value)
+		switch m.pane {
+		case PaneTree:
+			m.yank.Open()
+			return nil
+		case PaneOutput:
+			return m.toClipboard(m.outputTitle, ansi.Strip(m.lastOutput), nil)
+		}
+		if res, ok := m.viewer.QueryResult(); ok {
+			return m.toClipboard("query result", res, nil)
+		}
+		return m.toClipboard("kind.group/name", m.tree.Current().Value, nil)
 	case "enter", "d":
+		if m.pane == PaneOutput {
+			return nil
+		}
 		v := m.resByNode[m.tree.Current()]
-		m.viewer.SetContent(viewer.ContentInput{
-			Trace: v,
-		})
+		if v == nil {
+			return nil
+		}
+		m.viewer.SetContent(m.describeInput(m.tree.Current()))
 		m.pane = PaneSummary
+		return m.getEvents(v)
+	case "t":
+		if m.pane == PaneTree {
+			m.showView("timeline", func(trace *xplane.Resource, width int) string {
+				return renderTimeline(trace, width, time.Now())
+			})
+		}
+	case "R":
+		if m.pane == PaneTree {
+			m.showView("report", func(trace *xplane.Resource, _ int) string {
+				return xplane.NewReport(m.redactor.Resource(trace), time.Now()).String()
+			})
+		}
+	case "v":
+		if m.pane == PaneTree && m.loaded {
+			m.groupBy = m.groupBy.next()
+			m.renderNodes()
+		}
+	case "X":
+		if m.pane == PaneTree {
+			return m.writeBundle()
+		}
+	case "B":
+		if m.pane == PaneTree && m.loaded {
+			m.statusbar.StartBreadcrumb()
+		}
+	case "r", "p", "+", "-":
+		if m.pane == PaneTree {
+			return m.onWatchKey(msg.String())
+		}
 	case "q", "esc":
 		if m.pane == PaneTree {
-			return tea.Interrupt
+			return m.quit()
 		} else {
 			m.pane = PaneTree
 		}
+	default:
+		if m.pane == PaneTree {
+			return m.onAction(msg.String())
+		}
+	}
+
+	return nil
+}
+
+// quit cancels anything in flight, such as traces and live updates
+func (m *Model) quit() tea.Cmd {
+	m.stopLive()
+	m.cancel()
+	return tea.Interrupt
+}
+
+// actionOutputMsg carries the output of an action ran in the background
+type actionOutputMsg struct {
+	name   string
+	output []byte
+	err    error
+}
+
+func (m *Model) onAction(key string) tea.Cmd {
+	a, ok := lo.Find(m.actions, func(a action.Action) bool { return a.Key == key })
+	if !ok {
+		return nil
+	}
+
+	r := m.resByNode[m.tree.Current()]
+	if r == nil {
+		return nil
+	}
+
+	c, err := a.Cmd(r)
+	if err != nil {
+		return statusbar.Error("%s failed: %s", a.Name, err).Cmd()
+	}
+	if len(m.env) > 0 {
+		c.Env = append(os.Environ(), m.env...)
+	}
+
+	if a.Mode == action.ModeExec {
+		return tea.ExecProcess(c, func(err error) tea.Msg {
+			if err != nil {
+				return statusbar.Error("%s failed: %s", a.Name, err)
+			}
+			return statusbar.Info("%s done", a.Name)
+		})
+	}
+
+	m.lastOutput = ""
+	m.outputTitle = a.Name
+	m.outputView = nil
+	m.output.SetContent(textviewer.ContentInput{
+		Title:     a.Name,
+		SideTitle: m.tree.Current().Value,
+		Content:   fmt.Sprintf("Running %s ...", c.String()),
+	})
+	m.pane = PaneOutput
+
+	return func() tea.Msg {
+		out, err := c.CombinedOutput()
+		return actionOutputMsg{name: a.Name, output: out, err: err}
+	}
+}
+
+func (m *Model) onActionOutput(msg actionOutputMsg) {
+	m.lastOutput = string(msg.output)
+
+	content := m.lastOutput
+	if msg.err != nil {
+		content += fmt.Sprintf("\n%s failed: %s", msg.name, msg.err)
+	}
+	m.output.SetContent(textviewer.ContentInput{
+		Title:     msg.name,
+		SideTitle: m.tree.Current().Value,
+		Content:   content,
+	})
+}
+
+// showView displays a view of the whole trace in the output pane, which is
+// rendered again whenever the trace is refreshed
+func (m *Model) showView(title string, view func(trace *xplane.Resource, width int) string) {
+	m.outputTitle = title
+	m.outputView = view
+	m.renderView(false)
+	m.pane = PaneOutput
+}
+
+func (m *Model) renderView(keepPosition bool) {
+	// The viewport margin and padding take some room
+	m.lastOutput = m.outputView(m.trace, m.output.GetWidth()-4)
+	m.output.SetContent(textviewer.ContentInput{
+		Title:        m.outputTitle,
+		SideTitle:    m.tree.Current().Value,
+		Content:      m.lastOutput,
+		KeepPosition: keepPosition,
+	})
+}
+
+// onBreadcrumb moves the cursor to the ancestor picked in the breadcrumb
+func (m *Model) onBreadcrumb(msg statusbar.BreadcrumbSelectedMsg) {
+	if n := m.tree.Current(); n != nil {
+		m.tree.SelectAncestor(len(n.Path) - 1 - msg.Depth)
+	}
+}
+
+func (m *Model) onYank(msg menu.SelectedMsg) tea.Cmd {
+	r := m.resByNode[m.tree.Current()]
+	if r == nil {
+		return nil
+	}
+
+	for _, f := range yankFormats {
+		if f.item.Key == msg.Item.Key {
+			text, err := f.format(m.redactor.Resource(r))
+			return m.toClipboard(f.item.Label, text, err)
+		}
 	}
 
 	return nil
 }
 
+// toClipboard copies redacted text into the clipboard, reporting the outcome
+// to the statusbar
+func (m *Model) toClipboard(format, text string, err error) tea.Cmd {
+	if err == nil {
+		err = clipboard.Write(m.terminal, m.redactor.String(text))
+	}
+	if err != nil {
+		return statusbar.Error("yank failed: %s", err).Cmd()
+	}
+
+	return statusbar.Info("yanked %s", format).Cmd()
+}
+
+// describeInput returns what is described for a node
+func (m *Model) describeInput(n *tree.Node) viewer.ContentInput {
+	v := m.resByNode[n]
+	return viewer.ContentInput{
+		Trace:      v,
+		Parent:     m.parents[v],
+		Comparison: m.comparisons[n],
+	}
+}
+
 func addNodes(v *xplane.Resource, n *tree.Node, resByNode map[*tree.Node]*xplane.Resource) {
+	addNode(v, n, resByNode)
+
+	n.Children = make([]*tree.Node, len(v.Children))
+	for k, cv := range v.Children {
+		n.Children[k] = &tree.Node{}
+		addNodes(cv, n.Children[k], resByNode)
+	}
+}
+
+func addNode(v *xplane.Resource, n *tree.Node, resByNode map[*tree.Node]*xplane.Resource) {
 	name := fmt.Sprintf("%s/%s", v.Unstructured.GetKind(), v.Unstructured.GetName())
 	resStatus := xplane.GetResourceStatus(v, name)
 	group := v.Unstructured.GetObjectKind().GroupVersionKind().Group
 
+	n.ID = v.Key()
 	n.Key = name
 	n.Value = fmt.Sprintf("%s.%s/%s", v.Unstructured.GetKind(), group, v.Unstructured.GetName())
-	n.Children = make([]*tree.Node, len(v.Children))
 
 	if !resStatus.Ok {
 		n.Color = lipgloss.ANSIColor(ansi.Red)
@@ -130,9 +428,11 @@ func addNodes(v *xplane.Resource, n *tree.Node, resByNode map[*tree.Node]*xplane
 	}
 
 	resByNode[n] = v
+}
 
-	for k, cv := range v.Children {
-		n.Children[k] = &tree.Node{}
-		addNodes(cv, n.Children[k], resByNode)
+func addParents(v *xplane.Resource, parents map[*xplane.Resource]*xplane.Resource) {
+	for _, cv := range v.Children {
+		parents[cv] = v
+		addParents(cv, parents)
 	}
 }
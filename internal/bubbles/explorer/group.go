@@ -0,0 +1,104 @@
+package explorer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/tree"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// GroupBy defines how the trace is laid out: as a tree or flattened into
+// groups of resources
+type GroupBy int
+
+const (
+	GroupByNone GroupBy = iota
+	GroupByKind
+	GroupByGroup
+	GroupByStatus
+)
+
+func (g GroupBy) String() string {
+	switch g {
+	case GroupByKind:
+		return "kind"
+	case GroupByGroup:
+		return "group"
+	case GroupByStatus:
+		return "status"
+	default:
+		return "tree"
+	}
+}
+
+// next returns the layout following this one, going back to the tree
+func (g GroupBy) next() GroupBy {
+	return (g + 1) % (GroupByStatus + 1)
+}
+
+// key returns the group a resource belongs to
+func (g GroupBy) key(r *xplane.Resource) string {
+	switch g {
+	case GroupByKind:
+		return r.Unstructured.GroupVersionKind().GroupKind().String()
+	case GroupByGroup:
+		if group := r.Unstructured.GroupVersionKind().Group; group != "" {
+			return group
+		}
+		return "core"
+	default:
+		status := xplane.GetResourceStatus(r, "")
+		return fmt.Sprintf("Synced=%s Ready=%s", status.Synced, status.Ready)
+	}
+}
+
+// groupNodes flattens a tree of resources into collapsible groups, with headers
+// counting how many resources are in them and how many are not ok
+func groupNodes(root *tree.Node, resByNode map[*tree.Node]*xplane.Resource, by GroupBy) []*tree.Node {
+	groups := map[string]*tree.Node{}
+	failing := map[string]int{}
+
+	var walk func(n *tree.Node)
+	walk = func(n *tree.Node) {
+		children := n.Children
+		n.Children = nil
+
+		if r := resByNode[n]; r != nil {
+			key := by.key(r)
+			if groups[key] == nil {
+				groups[key] = &tree.Node{ID: "group:" + by.String() + ":" + key}
+			}
+			groups[key].Children = append(groups[key].Children, n)
+			if !xplane.GetResourceStatus(r, "").Ok {
+				failing[key]++
+			}
+		}
+
+		for _, c := range children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	nodes := make([]*tree.Node, 0, len(groups))
+	for _, k := range keys {
+		g := groups[k]
+		g.Key = fmt.Sprintf("%s (%d)", k, len(g.Children))
+		if failing[k] > 0 {
+			g.Key = fmt.Sprintf("%s (%d, %d not ok)", k, len(g.Children), failing[k])
+			g.Color = lipgloss.ANSIColor(ansi.Red)
+		}
+		g.Value = k
+		nodes = append(nodes, g)
+	}
+	return nodes
+}
@@ -0,0 +1,119 @@
+package explorer
+
+import (
+	"context"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// liveStartedMsg is sent once live updates are established
+type liveStartedMsg struct {
+	id      int
+	updates <-chan *xplane.Resource
+}
+
+// liveUpdateMsg carries an updated version of a resource in the trace
+type liveUpdateMsg struct {
+	id      int
+	r       *xplane.Resource
+	updates <-chan *xplane.Resource
+}
+
+// liveErrMsg is sent when live updates fail to start (err is set) or stop
+type liveErrMsg struct {
+	id  int
+	err error
+}
+
+// startLive replaces any live updates stream by one for the current trace
+func (m *Model) startLive() tea.Cmd {
+	m.stopLive()
+	if m.live == nil || !m.watch {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.liveCancel = cancel
+
+	id, live, trace := m.liveID, m.live, m.trace
+	return func() tea.Msg {
+		updates, err := live.Watch(ctx, trace)
+		if err != nil {
+			return liveErrMsg{id: id, err: err}
+		}
+		return liveStartedMsg{id: id, updates: updates}
+	}
+}
+
+func (m *Model) stopLive() {
+	m.liveID++
+	m.liveActive = false
+	if m.liveCancel != nil {
+		m.liveCancel()
+		m.liveCancel = nil
+	}
+}
+
+func waitLive(id int, updates <-chan *xplane.Resource) tea.Cmd {
+	return func() tea.Msg {
+		r, ok := <-updates
+		if !ok {
+			return liveErrMsg{id: id}
+		}
+		return liveUpdateMsg{id: id, r: r, updates: updates}
+	}
+}
+
+func (m *Model) onLiveStarted(msg liveStartedMsg) tea.Cmd {
+	if msg.id != m.liveID {
+		return nil
+	}
+
+	m.liveActive = true
+	m.liveSince = time.Now()
+	m.tickID++ // updates are pushed, so polling is not needed
+	return tea.Batch(waitLive(msg.id, msg.updates), m.watchStatus())
+}
+
+func (m *Model) onLiveUpdate(msg liveUpdateMsg) tea.Cmd {
+	if msg.id != m.liveID {
+		return nil
+	}
+
+	var cmd tea.Cmd
+	if trace, ok := xplane.Replace(m.trace, msg.r); ok {
+		cmd = m.setTrace(trace)
+	}
+	m.lastRefresh = time.Now()
+	return tea.Batch(cmd, waitLive(msg.id, msg.updates), m.watchStatus())
+}
+
+// onLiveErr falls back to polling. If the stream ended, the tree structure
+// might have changed, so it traces again as soon as possible.
+func (m *Model) onLiveErr(msg liveErrMsg) tea.Cmd {
+	if msg.id != m.liveID {
+		return nil
+	}
+
+	m.liveActive = false
+	if msg.err != nil {
+		m.logger.Warn("failed to watch trace, falling back to polling", "err", msg.err)
+		if apierrors.IsForbidden(msg.err) {
+			m.live = nil
+		}
+		return m.watchStatus() // the tick scheduled after the trace is still pending
+	}
+
+	if !m.watch {
+		return m.watchStatus()
+	}
+
+	// Streams ending too often must not trace more than polling would
+	if wait := m.watchInterval - time.Since(m.liveSince); wait > 0 {
+		return tea.Batch(m.schedule(wait), m.watchStatus())
+	}
+	return m.refresh()
+}
@@ -1,16 +1,27 @@
 package explorer
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"time"
 
+	"github.com/brunoluiz/crossplane-explorer/internal/action"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/statusbar"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/viewer"
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/menu"
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/tree"
+	textviewer "github.com/brunoluiz/crossplane-explorer/internal/bubbles/viewer"
+	"github.com/brunoluiz/crossplane-explorer/internal/notify"
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
 	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -28,26 +39,81 @@ type Pane string
 const (
 	PaneTree    Pane = "tree"
 	PaneSummary Pane = "summary"
+	PaneOutput  Pane = "output"
 )
 
+// reservedKeys are bound to built-in features, so they can't be used by
+// user-defined actions
+var reservedKeys = []string{
+	"ctrl+c", "q", "esc", "?", "y", "enter", "d", "r", "p", "+", "-", "t", "R", "X", "v", "B",
+	"up", "down", "k", "j", "left", "right", "h", "l", "pgup", "pgdown", "b", "f", " ", "u", "ctrl+u", "ctrl+d", "home", "g", "end", "G",
+}
+
+func IsReservedKey(k string) bool { return lo.Contains(reservedKeys, k) }
+
 type Tracer interface {
-	GetTrace() (*xplane.Resource, error)
+	GetTrace(ctx context.Context) (*xplane.Resource, error)
+}
+
+type EventsGetter interface {
+	GetEvents(ctx context.Context, r *xplane.Resource) ([]corev1.Event, error)
+}
+
+// LiveWatcher streams updated versions of the resources in a trace
+type LiveWatcher interface {
+	Watch(ctx context.Context, trace *xplane.Resource) (<-chan *xplane.Resource, error)
 }
 
 type Model struct {
 	tree          tree.Model
 	statusbar     *statusbar.Model // requires pointer here
 	viewer        viewer.Model
+	yank          menu.Model
+	output        textviewer.Model
+	actions       []action.Action
+	env           []string
 	tracer        Tracer
+	events        EventsGetter
+	notifier      *notify.Notifier
+	live          LiveWatcher
+	compare       Tracer
+	bundleDir     string
+	kubeContext   string
+	kubeCluster   string
+	redactor      *redact.Redactor
+	terminal      io.Writer
 	width         int
 	height        int
 	watch         bool
+	paused        bool // watching was turned off with "p"
 	watchInterval time.Duration
 	logger        *slog.Logger
+	ctx           context.Context
+	cancel        context.CancelFunc
 
-	pane      Pane
-	err       error
-	resByNode map[*tree.Node]*xplane.Resource
+	pane        Pane
+	err         error
+	loaded      bool
+	tracing     bool
+	tickID      int
+	lastRefresh time.Time
+	lastErr     error
+	failures    int
+	retryAt     time.Time
+	liveID      int
+	liveActive  bool
+	liveSince   time.Time
+	liveCancel  context.CancelFunc
+	trace       *xplane.Resource
+	resByNode   map[*tree.Node]*xplane.Resource
+	summary     xplane.Summary
+	groupBy     GroupBy
+	comparison  *xplane.Comparison
+	comparisons map[*tree.Node]*xplane.Comparison
+	parents     map[*xplane.Resource]*xplane.Resource
+	lastOutput  string
+	outputTitle string
+	outputView  func(trace *xplane.Resource, width int) string
 }
 
 type WithOpt func(*Model)
@@ -64,6 +130,65 @@ func WithWatchInterval(t time.Duration) func(*Model) {
 	}
 }
 
+// WithActions binds user-defined actions to keys in the tree, which must not
+// be reserved (see IsReservedKey)
+func WithActions(actions []action.Action) func(*Model) {
+	return func(m *Model) {
+		m.actions = actions
+	}
+}
+
+// WithTerminal sets where escape sequences, such as OSC52 yanks, are
+// written. It should be shared with the program rendering into the terminal.
+func WithTerminal(w io.Writer) func(*Model) {
+	return func(m *Model) {
+		m.terminal = w
+	}
+}
+
+// WithContext sets the parent context of all traces, cancelled on quit
+func WithContext(ctx context.Context) func(*Model) {
+	return func(m *Model) {
+		m.ctx = ctx
+	}
+}
+
+// WithCommandEnv adds environment variables to the commands ran by actions,
+// such as KUBECONFIG
+func WithCommandEnv(env []string) func(*Model) {
+	return func(m *Model) {
+		m.env = env
+	}
+}
+
+func WithEventsGetter(e EventsGetter) func(*Model) {
+	return func(m *Model) {
+		m.events = e
+	}
+}
+
+// WithLiveWatcher pushes updates as they happen while watching, instead of
+// polling. Polling is still used whenever watches fail.
+func WithLiveWatcher(w LiveWatcher) func(*Model) {
+	return func(m *Model) {
+		m.live = w
+	}
+}
+
+// WithRedactor redacts sensitive data from yanks, bundles and reports
+func WithRedactor(r *redact.Redactor) func(*Model) {
+	return func(m *Model) {
+		m.redactor = r
+	}
+}
+
+// WithNotifier sends notifications on status transitions between refreshes
+func WithNotifier(n *notify.Notifier) func(*Model) {
+	return func(m *Model) {
+		m.notifier = n
+	}
+}
+
 func New(
 	logger *slog.Logger,
 	treeModel tree.Model,
@@ -74,16 +199,23 @@ func New(
 ) *Model {
 	m := &Model{
 		logger:        logger,
+		ctx:           context.Background(),
 		tree:          treeModel,
 		statusbar:     &statusbarModel,
 		viewer:        viewerModel,
+		yank:          newYankMenu(),
+		output:        textviewer.New(),
 		tracer:        tracer,
 		width:         0,
 		height:        0,
 		watchInterval: 10 * time.Second,
+		bundleDir:     ".",
+		terminal:      os.Stdout,
+		tracing:       true, // Init always starts a trace
 
 		pane:      PaneTree,
 		resByNode: map[*tree.Node]*xplane.Resource{},
+		parents:   map[*xplane.Resource]*xplane.Resource{},
 	}
 	m.tree.OnSelectionChange = func(n *tree.Node) {
 		m.statusbar.SetPath(n.Path)
@@ -92,22 +224,37 @@ func New(
 	for _, opt := range opts {
 		opt(m)
 	}
+	m.ctx, m.cancel = context.WithCancel(m.ctx)
 
 	return m
 }
 
 func (m Model) getTrace() tea.Cmd {
+	if m.compare != nil {
+		return m.getComparison()
+	}
+
 	return func() tea.Msg {
-		res, err := m.tracer.GetTrace()
+		res, err := m.tracer.GetTrace(m.ctx)
 		if err != nil {
-			return err
+			return traceErrMsg{err: err}
 		}
 		return res
 	}
 }
 
+func (m Model) getEvents(r *xplane.Resource) tea.Cmd {
+	return func() tea.Msg {
+		if m.events == nil {
+			return viewer.EventsMsg{Trace: r, Err: errors.New("events are not supported by this trace source")}
+		}
+		events, err := m.events.GetEvents(m.ctx, r)
+		return viewer.EventsMsg{Trace: r, Events: events, Err: err}
+	}
+}
+
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.getTrace())
+	return tea.Batch(m.getTrace(), clock())
 }
 
 func (m Model) View() string {
@@ -118,11 +265,22 @@ func (m Model) View() string {
 	switch m.pane {
 	case PaneSummary:
 		return m.viewer.View()
+	case PaneOutput:
+		return m.output.View()
 	case PaneTree:
+		bottom := m.statusbar.View()
+		if m.yank.IsOpen() {
+			bottom = m.yank.View()
+		}
+		summary := ""
+		if m.loaded {
+			summary = renderSummary(m.summary, m.comparison, m.width)
+		}
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
-			lipgloss.NewStyle().Height(m.height-m.statusbar.GetHeight()).Render(m.tree.View()),
-			m.statusbar.View(),
+			summary,
+			lipgloss.NewStyle().Height(m.height-m.statusbar.GetHeight()-summaryHeight).Render(m.tree.View()),
+			bottom,
 		)
 	default:
 		return "No pane selected"
@@ -0,0 +1,35 @@
+package explorer
+
+import (
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/statusbar"
+	"github.com/brunoluiz/crossplane-explorer/internal/notify"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notify sends notifications for the transitions between two traces. The
+// first trace has nothing to compare against, so it never notifies.
+func (m *Model) notify(prev, curr *xplane.Resource) tea.Cmd {
+	if m.notifier == nil || !m.notifier.Enabled() || prev == nil {
+		return nil
+	}
+
+	transitions := m.notifier.Matching(notify.Diff(prev, curr))
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	cmds := make([]tea.Cmd, 0, len(transitions))
+	for _, t := range transitions {
+		cmds = append(cmds, func() tea.Msg {
+			if err := m.notifier.Notify(t); err != nil {
+				return statusbar.Error("notification failed: %s", err)
+			}
+			if t.To != "True" {
+				return statusbar.Warn("%s", t)
+			}
+			return statusbar.Info("%s", t)
+		})
+	}
+	return tea.Sequence(cmds...)
+}
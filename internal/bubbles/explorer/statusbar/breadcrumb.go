@@ -0,0 +1,130 @@
+package statusbar
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	breadcrumbHint = "←/→ select · enter jump · esc cancel"
+	// breadcrumbHintMinWidth leaves the path enough room on narrow terminals
+	breadcrumbHintMinWidth = 100
+	ellipsis               = "…"
+)
+
+// BreadcrumbSelectedMsg is sent when a path segment is picked in breadcrumb
+// mode. Depth is the segment index, starting from the root.
+type BreadcrumbSelectedMsg struct {
+	Depth int
+}
+
+// StartBreadcrumb enters breadcrumb mode, selecting the parent of the current
+// path segment
+func (m *Model) StartBreadcrumb() {
+	if len(m.path) == 0 {
+		return
+	}
+	m.browsing = true
+	m.crumb = max(len(m.path)-2, 0)
+}
+
+// IsBrowsing tells whether breadcrumb mode is on, in which case key presses
+// should be sent to the statusbar
+func (m *Model) IsBrowsing() bool { return m.browsing }
+
+func (m *Model) onBreadcrumbKey(msg tea.KeyMsg) tea.Cmd {
+	switch k := msg.String(); k {
+	case "left", "h":
+		m.crumb = max(m.crumb-1, 0)
+	case "right", "l":
+		m.crumb = min(m.crumb+1, len(m.path)-1)
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i, _ := strconv.Atoi(k); i <= len(m.path) {
+			m.crumb = i - 1
+		}
+	case "enter":
+		m.browsing = false
+		depth := m.crumb
+		return func() tea.Msg { return BreadcrumbSelectedMsg{Depth: depth} }
+	case "esc", "q":
+		m.browsing = false
+	}
+	return nil
+}
+
+// renderPath joins the path segments, eliding the middle ones so that the
+// selected segment, the root and as many of the last segments as possible fit
+func (m *Model) renderPath(width int) string {
+	n := len(m.path)
+	if n == 0 {
+		return ""
+	}
+	m.crumb = min(m.crumb, n-1)
+
+	labels := make([]string, n)
+	for i, p := range m.path {
+		labels[i] = p
+		if m.browsing && i < 9 {
+			labels[i] = fmt.Sprintf("%d:%s", i+1, p)
+		}
+	}
+
+	// The selected segment always shows, then the root and the last ones
+	shown := map[int]bool{n - 1: true}
+	if m.browsing {
+		shown = map[int]bool{m.crumb: true}
+	}
+	fits := func(i int) bool {
+		if shown[i] {
+			return true
+		}
+		with := maps.Clone(shown)
+		with[i] = true
+		return ansi.StringWidth(m.joinPath(labels, with, false)) <= width
+	}
+	for _, i := range []int{0, n - 1} {
+		if fits(i) {
+			shown[i] = true
+		}
+	}
+	// The last segments are added from the end, until one does not fit
+	for i := n - 2; i > 0; i-- {
+		if !fits(i) {
+			break
+		}
+		shown[i] = true
+	}
+
+	return m.joinPath(labels, shown, m.browsing)
+}
+
+// joinPath joins the shown labels, replacing each run of hidden ones by an
+// ellipsis. Segments are styled one by one, so the selection does not reset
+// the column colours.
+func (m *Model) joinPath(labels []string, shown map[int]bool, styled bool) string {
+	base := lipgloss.NewStyle()
+	selected := lipgloss.NewStyle()
+	if styled {
+		base = base.Foreground(m.neutralColor.Foreground).Background(m.neutralColor.Background)
+		selected = selected.Foreground(m.secondaryColor.Foreground).Background(m.secondaryColor.Background).Bold(true)
+	}
+
+	var parts []string
+	for i, l := range labels {
+		switch {
+		case shown[i] && styled && i == m.crumb:
+			parts = append(parts, selected.Render(l))
+		case shown[i]:
+			parts = append(parts, base.Render(l))
+		case i == 0 || shown[i-1]:
+			parts = append(parts, base.Render(ellipsis))
+		}
+	}
+	return strings.Join(parts, base.Render(m.pathSeparator))
+}
@@ -0,0 +1,60 @@
+package statusbar
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderPath(t *testing.T) {
+	type args struct {
+		width    int
+		browsing bool
+		crumb    int
+	}
+
+	type want struct {
+		path string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Fits": {
+			reason: "Should show all segments if they fit",
+			args:   args{width: 20},
+			want:   want{path: "root/a/b/c/d"},
+		},
+		"ElideMiddle": {
+			reason: "Should elide the middle segments, keeping the root and as many of the last ones as fit",
+			args:   args{width: 10},
+			want:   want{path: "root/…/c/d"},
+		},
+		"ElideRoot": {
+			reason: "Should elide the root if it does not fit along with the last segment",
+			args:   args{width: 6},
+			want:   want{path: "…/c/d"},
+		},
+		"Selected": {
+			reason: "Should always show the selected segment while browsing",
+			args:   args{width: 16, browsing: true, crumb: 1},
+			want:   want{path: "1:root/2:a/…/5:d"},
+		},
+		"SelectedNarrow": {
+			reason: "Should elide everything around the selected segment if nothing else fits",
+			args:   args{width: 10, browsing: true, crumb: 2},
+			want:   want{path: "…/3:b/…"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New(WithInitialPath([]string{"root", "a", "b", "c", "d"}), WithPathSeparator("/"))
+			m.browsing, m.crumb = tc.args.browsing, tc.args.crumb
+
+			if got := ansi.Strip(m.renderPath(tc.args.width)); got != tc.want.path {
+				t.Errorf("%s\nrenderPath(%d) = %q, want %q", tc.reason, tc.args.width, got, tc.want.path)
+			}
+		})
+	}
+}
@@ -1,17 +1,39 @@
 package statusbar
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"time"
 
-func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
-	m.statusbar.FourthColumn = ""
-	m.statusbar.FourthColumnColors = m.neutralColor
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WatchStatusMsg reports the state of the trace watcher. If the last refresh
+// failed, Err is set and it will be retried at RetryAt (if watching). Live is
+// set while updates are pushed instead of polled, and Paused once watching
+// was turned off by the user.
+type WatchStatusMsg struct {
+	Enabled     bool
+	Paused      bool
+	Live        bool
+	Interval    time.Duration
+	Refreshing  bool
+	LastRefresh time.Time
+	Err         error
+	RetryAt     time.Time
+}
 
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		cmd = m.onResize(msg)
 	case tea.KeyMsg:
-		cmd = m.onKey(msg)
+		if m.browsing {
+			cmd = m.onBreadcrumbKey(msg)
+		}
+	case MessageMsg:
+		m.onMessage(msg)
+	case WatchStatusMsg:
+		m.watch = msg
 	}
 
 	var statusbarCmd tea.Cmd
@@ -24,13 +46,3 @@ func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
 	m.statusbar.Width = msg.Width
 	return nil
 }
-
-func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
-	//nolint // allow usage of switch
-	switch msg.String() {
-	case "y":
-		m.statusbar.FourthColumn = "yanked"
-		m.statusbar.FourthColumnColors = m.secondaryColor
-	}
-	return nil
-}
@@ -0,0 +1,89 @@
+package statusbar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mistakenelf/teacup/statusbar"
+)
+
+// staleFactor defines after how many missed intervals a trace is stale
+const staleFactor = 3
+
+// SetFilter shows what narrows or rearranges the tree, such as a group-by.
+// An empty filter hides the indicator.
+func (m *Model) SetFilter(filter string) { m.filter = filter }
+
+// SetNodeCount shows how many resources are in the tree
+func (m *Model) SetNodeCount(n int) { m.nodeCount = n }
+
+// renderContext shows which context or comparison is explored and whether
+// it gets redacted
+func (m *Model) renderContext() {
+	m.statusbar.FirstColumn = "$"
+	if m.kubeContext != "" {
+		m.statusbar.FirstColumn = "⎈ " + m.kubeContext
+	}
+	if m.comparison != "" {
+		m.statusbar.FirstColumn = m.comparison
+	}
+	if m.redacted {
+		m.statusbar.FirstColumn += " · 🔒 redacted"
+	}
+}
+
+// renderIndicators shows the active filter, the number of resources and the
+// watch state, coloured by the latter
+func (m *Model) renderIndicators(now time.Time) {
+	var parts []string
+	if m.filter != "" {
+		parts = append(parts, "⧩ "+m.filter)
+	}
+	if m.nodeCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d resources", m.nodeCount))
+	}
+
+	watch, colors := m.watchStatus(now)
+	m.statusbar.ThirdColumn = strings.Join(append(parts, watch), " │ ")
+	m.statusbar.ThirdColumnColors = colors
+}
+
+func (m *Model) watchStatus(now time.Time) (string, statusbar.ColorConfig) {
+	w := m.watch
+	if w.Err != nil {
+		retry := "press r to retry"
+		switch wait := w.RetryAt.Sub(now).Round(time.Second); {
+		case w.Enabled && wait > 0:
+			retry = fmt.Sprintf("retry in %s", wait)
+		case w.Enabled:
+			retry = "retrying"
+		}
+
+		return fmt.Sprintf("refresh failed: %s (%s)", ansi.Truncate(w.Err.Error(), 40, "…"), retry), m.errorColor
+	}
+
+	if w.LastRefresh.IsZero() {
+		return "loading…", m.neutralColor
+	}
+
+	state := "manual"
+	switch {
+	case w.Paused:
+		state = "paused"
+	case w.Enabled && w.Live:
+		state = "live"
+	case w.Enabled:
+		state = fmt.Sprintf("every %s", w.Interval)
+	}
+	if w.Refreshing {
+		state = "refreshing…"
+	}
+
+	age := now.Sub(w.LastRefresh).Truncate(time.Second)
+	if w.Enabled && !w.Live && age > staleFactor*w.Interval {
+		return fmt.Sprintf("%s · %s ago", state, age), m.warnColor
+	}
+	return fmt.Sprintf("%s · %s ago", state, age), m.neutralColor
+}
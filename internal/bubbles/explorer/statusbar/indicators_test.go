@@ -0,0 +1,59 @@
+package statusbar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchStatus(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	type args struct {
+		watch WatchStatusMsg
+	}
+
+	type want struct {
+		status string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Loading": {
+			reason: "Should show the trace is loading until the first refresh",
+			args:   args{watch: WatchStatusMsg{}},
+			want:   want{status: "loading…"},
+		},
+		"Manual": {
+			reason: "Should not show watching as paused if it was never enabled",
+			args:   args{watch: WatchStatusMsg{LastRefresh: now.Add(-time.Minute)}},
+			want:   want{status: "manual · 1m0s ago"},
+		},
+		"Paused": {
+			reason: "Should show watching as paused once turned off",
+			args:   args{watch: WatchStatusMsg{Paused: true, LastRefresh: now.Add(-time.Minute)}},
+			want:   want{status: "paused · 1m0s ago"},
+		},
+		"Interval": {
+			reason: "Should show the interval while watching",
+			args:   args{watch: WatchStatusMsg{Enabled: true, Interval: 5 * time.Second, LastRefresh: now.Add(-time.Second)}},
+			want:   want{status: "every 5s · 1s ago"},
+		},
+		"Live": {
+			reason: "Should show live updates",
+			args:   args{watch: WatchStatusMsg{Enabled: true, Live: true, LastRefresh: now.Add(-time.Second)}},
+			want:   want{status: "live · 1s ago"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New()
+			m.watch = tc.args.watch
+
+			if got, _ := m.watchStatus(now); got != tc.want.status {
+				t.Errorf("%s\nwatchStatus() = %q, want %q", tc.reason, got, tc.want.status)
+			}
+		})
+	}
+}
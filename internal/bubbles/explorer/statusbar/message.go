@@ -0,0 +1,83 @@
+package statusbar
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Level defines how a message is highlighted and for how long it is shown
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelWarn
+	LevelError
+)
+
+const (
+	messageTTL      = 5 * time.Second
+	errorMessageTTL = 10 * time.Second
+)
+
+// MessageMsg shows a message in the statusbar until it expires, replacing
+// any previous one. TTL defaults to a few seconds, depending on the level.
+type MessageMsg struct {
+	Level Level
+	Text  string
+	TTL   time.Duration
+}
+
+func Info(format string, args ...any) MessageMsg {
+	return MessageMsg{Level: LevelInfo, Text: fmt.Sprintf(format, args...)}
+}
+
+func Warn(format string, args ...any) MessageMsg {
+	return MessageMsg{Level: LevelWarn, Text: fmt.Sprintf(format, args...)}
+}
+
+func Error(format string, args ...any) MessageMsg {
+	return MessageMsg{Level: LevelError, Text: fmt.Sprintf(format, args...)}
+}
+
+// Cmd sends the message, for components which emit it straight away
+func (msg MessageMsg) Cmd() tea.Cmd {
+	return func() tea.Msg { return msg }
+}
+
+func (m *Model) onMessage(msg MessageMsg) {
+	ttl := msg.TTL
+	if ttl <= 0 {
+		ttl = messageTTL
+		if msg.Level == LevelError {
+			ttl = errorMessageTTL
+		}
+	}
+
+	m.message = msg
+	m.messageExpiry = time.Now().Add(ttl)
+}
+
+// renderMessage shows the breadcrumb hint or the latest message, if it did
+// not expire yet
+func (m *Model) renderMessage(now time.Time) {
+	m.statusbar.FourthColumn = ""
+	m.statusbar.FourthColumnColors = m.neutralColor
+
+	switch {
+	case m.browsing && m.statusbar.Width >= breadcrumbHintMinWidth:
+		m.statusbar.FourthColumn = breadcrumbHint
+		m.statusbar.FourthColumnColors = m.secondaryColor
+	case m.message.Text != "" && now.Before(m.messageExpiry):
+		m.statusbar.FourthColumn = m.message.Text
+		switch m.message.Level {
+		case LevelWarn:
+			m.statusbar.FourthColumnColors = m.warnColor
+		case LevelError:
+			m.statusbar.FourthColumnColors = m.errorColor
+		default:
+			m.statusbar.FourthColumnColors = m.secondaryColor
+		}
+	}
+}
@@ -2,7 +2,7 @@ package statusbar
 
 import (
 	"fmt"
-	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -12,21 +12,38 @@ import (
 
 type Model struct {
 	statusbar      statusbar.Model
+	watch          WatchStatusMsg
+	message        MessageMsg
+	messageExpiry  time.Time
+	kubeContext    string
+	comparison     string
+	redacted       bool
+	filter         string
+	nodeCount      int
 	path           []string
 	pathSeparator  string
 	rootSymbol     string
+	browsing       bool
+	crumb          int
 	primaryColor   statusbar.ColorConfig
 	secondaryColor statusbar.ColorConfig
 	neutralColor   statusbar.ColorConfig
+	errorColor     statusbar.ColorConfig
+	warnColor      statusbar.ColorConfig
 }
 
 type config struct {
+	kubeContext    string
+	comparison     string
+	redacted       bool
 	path           []string
 	pathSeparator  string
 	rootSymbol     string
 	primaryColor   statusbar.ColorConfig
 	secondaryColor statusbar.ColorConfig
 	neutralColor   statusbar.ColorConfig
+	errorColor     statusbar.ColorConfig
+	warnColor      statusbar.ColorConfig
 }
 
 type WithOpt func(*config)
@@ -43,6 +60,34 @@ func WithNeutralStatusColor(cl statusbar.ColorConfig) func(c *config) {
 	return func(c *config) { c.neutralColor = cl }
 }
 
+func WithErrorStatusColor(cl statusbar.ColorConfig) func(c *config) {
+	return func(c *config) { c.errorColor = cl }
+}
+
+func WithWarnStatusColor(cl statusbar.ColorConfig) func(c *config) {
+	return func(c *config) { c.warnColor = cl }
+}
+
+// WithKubeContext shows which context and cluster are in use
+func WithKubeContext(context, cluster string) func(c *config) {
+	return func(c *config) {
+		c.kubeContext = context
+		if cluster != "" && cluster != context {
+			c.kubeContext = fmt.Sprintf("%s (%s)", context, cluster)
+		}
+	}
+}
+
+// WithComparison shows which traces are being compared
+func WithComparison(left, right string) func(c *config) {
+	return func(c *config) { c.comparison = left + " ⇄ " + right }
+}
+
+// WithRedaction shows whether yanks, bundles and reports are redacted
+func WithRedaction(enabled bool) func(c *config) {
+	return func(c *config) { c.redacted = enabled }
+}
+
 func WithPathSeparator(p string) func(c *config) {
 	return func(c *config) { c.pathSeparator = p }
 }
@@ -71,6 +116,14 @@ func New(opts ...WithOpt) Model {
 			Foreground: lipgloss.AdaptiveColor{Dark: itoa(ansi.White), Light: itoa(ansi.White)},
 			Background: lipgloss.AdaptiveColor{Light: itoa(ansi.BrightBlack), Dark: itoa(ansi.BrightBlack)},
 		},
+		errorColor: statusbar.ColorConfig{
+			Foreground: lipgloss.AdaptiveColor{Dark: itoa(ansi.White), Light: itoa(ansi.White)},
+			Background: lipgloss.AdaptiveColor{Light: itoa(ansi.Red), Dark: itoa(ansi.Red)},
+		},
+		warnColor: statusbar.ColorConfig{
+			Foreground: lipgloss.AdaptiveColor{Dark: itoa(ansi.Black), Light: itoa(ansi.Black)},
+			Background: lipgloss.AdaptiveColor{Light: itoa(ansi.Yellow), Dark: itoa(ansi.Yellow)},
+		},
 	}
 	for _, opt := range opts {
 		opt(&cfg)
@@ -82,10 +135,11 @@ func New(opts ...WithOpt) Model {
 		cfg.neutralColor,
 		cfg.neutralColor,
 	)
-	s.FirstColumn = "$"
-	s.SecondColumn = strings.Join(cfg.path, cfg.pathSeparator)
 
 	return Model{
+		kubeContext:    cfg.kubeContext,
+		comparison:     cfg.comparison,
+		redacted:       cfg.redacted,
 		path:           cfg.path,
 		pathSeparator:  cfg.pathSeparator,
 		rootSymbol:     cfg.rootSymbol,
@@ -93,15 +147,33 @@ func New(opts ...WithOpt) Model {
 		primaryColor:   cfg.primaryColor,
 		secondaryColor: cfg.secondaryColor,
 		neutralColor:   cfg.neutralColor,
+		errorColor:     cfg.errorColor,
+		warnColor:      cfg.warnColor,
 	}
 }
 
 func (m Model) Init() tea.Cmd { return nil }
-func (m *Model) View() string { return m.statusbar.View() }
+
+func (m *Model) View() string {
+	now := time.Now()
+	m.renderContext()
+	m.renderIndicators(now)
+	m.renderMessage(now)
+	m.statusbar.SecondColumn = m.renderPath(m.pathWidth())
+	return m.statusbar.View()
+}
+
+// pathWidth is the room left for the path by the other columns, following
+// how the statusbar sizes and truncates them (paddings and a "..." tail)
+func (m *Model) pathWidth() int {
+	w := min(ansi.StringWidth(m.statusbar.FirstColumn), 30) +
+		ansi.StringWidth(m.statusbar.ThirdColumn) +
+		ansi.StringWidth(m.statusbar.FourthColumn)
+	return m.statusbar.Width - w - 3*2 - 3 - 3
+}
 
 func (m *Model) GetHeight() int { return statusbar.Height }
 
 func (m *Model) SetPath(path []string) {
 	m.path = path
-	m.statusbar.SecondColumn = strings.Join(m.path, m.pathSeparator)
 }
@@ -0,0 +1,92 @@
+package explorer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// summaryHeight is the number of lines taken by the summary above the tree
+const summaryHeight = 1
+
+var (
+	summaryStyle      = lipgloss.NewStyle().PaddingLeft(1)
+	summaryOkStyle    = lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.Green))
+	summaryWarnStyle  = lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.Yellow))
+	summaryErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.Red))
+	summaryFaintStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// renderSummary draws a single line with the root status, the number of
+// resources per status and kind. While comparing, both traces are summarised
+// instead, along with the number of changes.
+func renderSummary(s xplane.Summary, comparison *xplane.Comparison, width int) string {
+	sep := summaryFaintStyle.Render(" │ ")
+
+	var parts []string
+	if comparison == nil {
+		parts = append(parts, renderSummaryStatus(s)...)
+
+		kinds := make([]string, 0, len(s.ByKind))
+		for _, k := range s.ByKind {
+			kinds = append(kinds, fmt.Sprintf("%s %d", k.Kind, k.Count))
+		}
+		parts = append(parts, summaryFaintStyle.Render(strings.Join(kinds, " · ")))
+	} else {
+		for _, side := range []struct {
+			label   string
+			summary xplane.Summary
+		}{
+			{"left", s},
+			{"right", xplane.NewSummary(comparison.Right)},
+		} {
+			parts = append(parts, summaryFaintStyle.Render(side.label)+" "+strings.Join(renderSummaryStatus(side.summary), ", "))
+		}
+
+		changes := comparison.Changes()
+		parts = append(parts, strings.Join([]string{
+			summaryWarnStyle.Render(fmt.Sprintf("%d status", changes[xplane.ChangeStatus])),
+			summaryErrorStyle.Render(fmt.Sprintf("%d missing", changes[xplane.ChangeMissing])),
+			summaryOkStyle.Render(fmt.Sprintf("%d extra", changes[xplane.ChangeExtra])),
+		}, ", "))
+	}
+
+	return summaryStyle.Render(ansi.Truncate(strings.Join(parts, sep), max(width-1, 0), "…"))
+}
+
+// renderSummaryStatus returns the root status and the number of resources
+// per status
+func renderSummaryStatus(s xplane.Summary) []string {
+	rootStyle := summaryErrorStyle
+	if s.Root.Ok {
+		rootStyle = summaryOkStyle
+	}
+	root := rootStyle.Render(fmt.Sprintf("● Synced=%s Ready=%s", s.Root.Synced, s.Root.Ready))
+
+	counts := []string{
+		fmt.Sprintf("%d resources", s.Total),
+		summaryOkStyle.Render(fmt.Sprintf("%d ready", s.Ready)),
+	}
+	if s.NotReady > 0 {
+		counts = append(counts, summaryWarnStyle.Render(fmt.Sprintf("%d not ready", s.NotReady)))
+	}
+	for _, c := range []struct {
+		count int
+		label string
+		style lipgloss.Style
+	}{
+		{s.NotSynced, "not synced", summaryErrorStyle},
+		{s.Errors, "errors", summaryErrorStyle},
+		{s.Deleting, "deleting", summaryErrorStyle},
+		{s.Paused, "paused", summaryWarnStyle},
+	} {
+		if c.count > 0 {
+			counts = append(counts, c.style.Render(fmt.Sprintf("%d %s", c.count, c.label)))
+		}
+	}
+
+	return []string{root, strings.Join(counts, ", ")}
+}
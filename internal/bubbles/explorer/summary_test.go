@@ -0,0 +1,71 @@
+package explorer
+
+import (
+	"testing"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/charmbracelet/x/ansi"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResource(kind, name, ready string, children ...*xplane.Resource) *xplane.Resource {
+	return &xplane.Resource{
+		Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata": map[string]any{
+				"name":        name,
+				"annotations": map[string]any{"crossplane.io/composition-resource-name": name},
+			},
+			"status": map[string]any{"conditions": []any{
+				map[string]any{"type": "Synced", "status": "True"},
+				map[string]any{"type": "Ready", "status": ready},
+			}},
+		}},
+		Children: children,
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	left := newResource("XBucket", "root", "True",
+		newResource("Bucket", "bucket", "True"),
+		newResource("Role", "role", "True"),
+	)
+	right := newResource("XBucket", "root", "False",
+		newResource("Bucket", "bucket", "False"),
+	)
+
+	type args struct {
+		comparison *xplane.Comparison
+	}
+
+	type want struct {
+		summary string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Trace": {
+			reason: "Should summarise the trace by status and kind",
+			args:   args{},
+			want:   want{summary: " ● Synced=True Ready=True │ 3 resources, 3 ready │ Bucket 1 · Role 1 · XBucket 1"},
+		},
+		"Compare": {
+			reason: "Should summarise both traces, along with the changes between them",
+			args:   args{comparison: xplane.Compare(left, right)},
+			want: want{summary: " left ● Synced=True Ready=True, 3 resources, 3 ready" +
+				" │ right ● Synced=True Ready=False, 2 resources, 0 ready, 2 not ready" +
+				" │ 2 status, 1 missing, 0 extra"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ansi.Strip(renderSummary(xplane.NewSummary(left), tc.args.comparison, 200))
+			if got != tc.want.summary {
+				t.Errorf("%s\nrenderSummary() = %q, want %q", tc.reason, got, tc.want.summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package explorer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	timelineLabelWidth    = 40
+	timelineDurationWidth = 8
+	timelineTimeFormat    = "01-02 15:04:05"
+)
+
+var (
+	timelineReadyStyle    = lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.Green))
+	timelinePendingStyle  = lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.Yellow))
+	timelineDeletingStyle = lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.Red))
+	timelineHintStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+// renderTimeline draws a Gantt-style chart of the trace, with one row per
+// resource spanning from its creation until it became ready (or now)
+func renderTimeline(trace *xplane.Resource, width int, now time.Time) string {
+	milestones := xplane.GetMilestones(trace)
+
+	var start, end time.Time
+	for _, ms := range milestones {
+		if ms.Created.IsZero() {
+			continue
+		}
+		if start.IsZero() || ms.Created.Before(start) {
+			start = ms.Created
+		}
+		for _, t := range []time.Time{ms.Created, ms.Synced.LastTransitionTime.Time, ms.Ready.LastTransitionTime.Time, ms.Deleted} {
+			if t.After(end) {
+				end = t
+			}
+		}
+		if _, ok := ms.IsReady(); !ok && now.After(end) {
+			end = now
+		}
+	}
+	if start.IsZero() {
+		return "No timestamps found in the trace"
+	}
+
+	labelWidth := min(timelineLabelWidth, width/3)
+	chartWidth := max(10, width-labelWidth-timelineDurationWidth-2)
+	span := max(end.Sub(start), time.Second)
+	col := func(t time.Time) int {
+		c := int(int64(t.Sub(start)) * int64(chartWidth-1) / int64(span))
+		return min(max(c, 0), chartWidth-1)
+	}
+
+	from, to := start.Format(timelineTimeFormat), end.Format(timelineTimeFormat)
+	rows := []string{
+		fmt.Sprintf(
+			"%-*s %s%s%s",
+			labelWidth, "RESOURCE",
+			from, strings.Repeat(" ", max(1, chartWidth-len(from)-len(to))), to,
+		),
+	}
+
+	for _, ms := range milestones {
+		obj := ms.Resource.Unstructured
+		label := strings.Repeat(" ", ms.Depth) + fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+		label = ansi.Truncate(label, labelWidth, "…")
+		label += strings.Repeat(" ", labelWidth-ansi.StringWidth(label))
+
+		if ms.Created.IsZero() {
+			rows = append(rows, label+" "+timelineHintStyle.Render("no timestamps"))
+			continue
+		}
+
+		readyAt, ready := ms.IsReady()
+		barEnd, style := now, timelinePendingStyle
+		switch {
+		case !ms.Deleted.IsZero():
+			barEnd, style = ms.Deleted, timelineDeletingStyle
+		case ready:
+			barEnd, style = readyAt, timelineReadyStyle
+		}
+
+		bar := []rune(strings.Repeat(" ", chartWidth))
+		for i := col(ms.Created); i <= col(barEnd); i++ {
+			bar[i] = '─'
+		}
+		bar[col(ms.Created)] = '●'
+		if t := ms.Synced.LastTransitionTime; !t.IsZero() {
+			bar[col(t.Time)] = 'S'
+		}
+		if t := ms.Ready.LastTransitionTime; !t.IsZero() {
+			bar[col(t.Time)] = 'R'
+		}
+		if !ms.Deleted.IsZero() {
+			bar[col(ms.Deleted)] = '✕'
+		}
+
+		took := duration.HumanDuration(now.Sub(ms.Created)) + "…"
+		if ready {
+			took = duration.HumanDuration(readyAt.Sub(ms.Created))
+		}
+
+		rows = append(rows, fmt.Sprintf("%s %s %*s", label, style.Render(string(bar)), timelineDurationWidth, took))
+	}
+
+	rows = append(rows,
+		"",
+		timelineHintStyle.Render("● created  S synced  R ready  ✕ deleted"),
+		timelineHintStyle.Render("green: ready · yellow: not ready yet (time so far) · red: being deleted"),
+	)
+
+	return strings.Join(rows, "\n")
+}
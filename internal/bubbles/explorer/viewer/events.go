@@ -1,12 +1,140 @@
 package viewer
 
 import (
+	"slices"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// The tab bar is rendered on top of the inner viewer
+		msg.Height -= lipgloss.Height(m.tabsView())
+		var viewerCmd tea.Cmd
+		m.viewer, viewerCmd = m.viewer.Update(msg)
+		m.render()
+		return m, viewerCmd
+	case EventsMsg:
+		m.onEvents(msg)
+		return m, nil
+	case tea.KeyMsg:
+		if m.querying {
+			return m, m.onQueryKey(msg)
+		}
+		if !m.viewer.IsCapturingInput() {
+			if cmd, ok := m.onKey(msg); ok {
+				return m, cmd
+			}
+		}
+	}
+
+	// Keeps the prompt cursor blinking
+	var queryCmd tea.Cmd
+	if m.querying {
+		m.query, queryCmd = m.query.Update(msg)
+	}
+
 	var viewerCmd tea.Cmd
 	m.viewer, viewerCmd = m.viewer.Update(msg)
 
-	return m, tea.Batch(viewerCmd)
+	return m, tea.Batch(queryCmd, viewerCmd)
+}
+
+// onKey handles tab navigation, toggles and prompts, returning true if the
+// key was consumed
+func (m *Model) onKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "tab":
+		m.moveTab(1)
+	case "shift+tab":
+		m.moveTab(-1)
+	case "c":
+		if m.tab != TabYAML && m.tab != TabDiff {
+			return nil, false
+		}
+		m.rawYAML = !m.rawYAML
+	case ":":
+		m.querying = true
+		m.historyCursor = len(m.queries)
+		m.query.SetValue("")
+		return m.query.Focus(), true
+	default:
+		return nil, false
+	}
+
+	m.render()
+	return nil, true
+}
+
+func (m *Model) moveTab(delta int) {
+	visible := m.visibleTabs()
+	idx := max(slices.Index(visible, m.tab), 0)
+	m.tab = visible[(idx+delta+len(visible))%len(visible)]
+}
+
+func (m *Model) onQueryKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.querying = false
+		m.query.Blur()
+		return nil
+	case "enter":
+		m.querying = false
+		m.query.Blur()
+		if q := m.query.Value(); q != "" {
+			m.queries = lo.Without(m.queries, q)
+			m.queries = append(m.queries, q)
+			if len(m.queries) > maxQueryHistory {
+				m.queries = m.queries[1:]
+			}
+		}
+		m.tab = TabQuery
+		m.runQuery()
+		m.render()
+		return nil
+	case "up":
+		m.onQueryHistory(-1)
+		return nil
+	case "down":
+		m.onQueryHistory(1)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.query, cmd = m.query.Update(msg)
+	return cmd
+}
+
+// onQueryHistory moves through previous queries, starting from the most
+// recent one. Moving past the last one clears the prompt.
+func (m *Model) onQueryHistory(delta int) {
+	m.historyCursor = lo.Clamp(m.historyCursor+delta, 0, len(m.queries))
+	if m.historyCursor == len(m.queries) {
+		m.query.SetValue("")
+		return
+	}
+	m.query.SetValue(m.queries[m.historyCursor])
+	m.query.CursorEnd()
+}
+
+func (m *Model) onEvents(msg EventsMsg) {
+	// Discard events from a previously described resource
+	if m.input.Trace == nil || msg.Trace.Key() != m.input.Trace.Key() {
+		return
+	}
+
+	m.events = msg.Events
+	sort.SliceStable(m.events, func(i, j int) bool {
+		return eventTime(m.events[i]).After(eventTime(m.events[j]))
+	})
+	m.eventsErr = msg.Err
+	m.eventsLoaded = true
+	if m.tab == TabEvents {
+		m.renderKeepingPosition(true)
+	}
 }
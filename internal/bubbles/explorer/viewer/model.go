@@ -2,92 +2,233 @@ package viewer
 
 import (
 	"fmt"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/viewer"
 	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/goccy/go-yaml"
-	"github.com/samber/lo"
-	k8sv1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
+type Tab int
+
+const (
+	TabOverview Tab = iota
+	TabConditions
+	TabYAML
+	TabEvents
+	TabRelations
+	TabQuery
+	TabDiff
+)
+
+var tabs = []Tab{TabOverview, TabConditions, TabYAML, TabEvents, TabRelations, TabQuery}
+
+// maxQueryHistory is the number of past queries kept around
+const maxQueryHistory = 20
+
+func (t Tab) String() string {
+	switch t {
+	case TabOverview:
+		return "Overview"
+	case TabConditions:
+		return "Conditions"
+	case TabYAML:
+		return "YAML"
+	case TabEvents:
+		return "Events"
+	case TabRelations:
+		return "Relations"
+	case TabQuery:
+		return "Query"
+	case TabDiff:
+		return "Diff"
+	default:
+		return "Unknown"
+	}
+}
+
+// YAMLFilter transforms an object before it gets rendered in the YAML tab
+type YAMLFilter func(obj map[string]any) map[string]any
+
 type Model struct {
 	viewer viewer.Model
 
-	styles Styles
+	styles      Styles
+	tab         Tab
+	input       ContentInput
+	yamlFilters []YAMLFilter
+	rawYAML     bool
+	diffLabels  [2]string
+
+	events       []corev1.Event
+	eventsErr    error
+	eventsLoaded bool
+
+	query         textinput.Model
+	querying      bool
+	queries       []string
+	historyCursor int
+	queryResult   string
+	queryErr      error
 }
 
-func New() Model {
-	return Model{
+type WithOpt func(*Model)
+
+// WithYAMLFilters sets the filters applied, in order, to objects before they
+// get rendered in the YAML tab. Filters can be toggled off at runtime.
+func WithYAMLFilters(f ...YAMLFilter) func(*Model) {
+	return func(m *Model) {
+		m.yamlFilters = f
+	}
+}
+
+// WithDiffLabels names the compared traces in the diff tab
+func WithDiffLabels(left, right string) func(*Model) {
+	return func(m *Model) {
+		m.diffLabels = [2]string{left, right}
+	}
+}
+
+func New(opts ...WithOpt) Model {
+	query := textinput.New()
+	query.Prompt = "query: "
+	query.Placeholder = "{.status.atProvider} or .metadata.labels"
+
+	m := Model{
 		viewer: viewer.New(),
 		styles: DefaultStyles(),
+		tab:    TabOverview,
+		query:  query,
+
+		diffLabels: [2]string{"left", "right"},
+	}
+
+	for _, opt := range opts {
+		opt(&m)
 	}
+
+	return m
 }
 
 func (m Model) Init() tea.Cmd { return nil }
-func (m Model) View() string  { return m.viewer.View() }
+func (m Model) View() string {
+	return lipgloss.JoinVertical(lipgloss.Left, m.tabsView(), m.viewer.View())
+}
+
+func (m Model) IsCapturingInput() bool { return m.querying || m.viewer.IsCapturingInput() }
+
+// QueryResult returns the result of the last query, if it is being displayed
+func (m Model) QueryResult() (string, bool) {
+	if m.tab != TabQuery || m.queryErr != nil || m.queryResult == "" {
+		return "", false
+	}
+	return m.queryResult, true
+}
 
 type ContentInput struct {
-	Trace *xplane.Resource
+	Trace  *xplane.Resource
+	Parent *xplane.Resource
+	// Comparison is set while comparing traces, enabling the diff tab
+	Comparison *xplane.Comparison
+}
+
+// EventsMsg carries the Kubernetes events related to a described resource
+type EventsMsg struct {
+	Trace  *xplane.Resource
+	Events []corev1.Event
+	Err    error
 }
 
 func (m *Model) SetContent(msg ContentInput) {
-	val, err := yaml.Marshal(msg.Trace.Unstructured.Object)
-	if err != nil {
-		panic(err)
-	}
+	m.input = msg
+	m.events = nil
+	m.eventsErr = nil
+	m.eventsLoaded = false
+	m.runQuery()
+	m.render()
+}
 
-	hr := "────"
-	if m.viewer.GetWidth() > 4 {
-		hr = strings.Repeat("─", m.viewer.GetWidth()-4)
+// Refresh replaces the described resource by a more recent version of it. If
+// it is the same resource, the current tab, scroll position and events are kept.
+func (m *Model) Refresh(msg ContentInput) {
+	if m.input.Trace == nil || msg.Trace == nil || m.input.Trace.Key() != msg.Trace.Key() {
+		m.SetContent(msg)
+		return
 	}
 
-	m.viewer.SetContent(viewer.ContentInput{
-		Title:     fmt.Sprintf("%s/%s", msg.Trace.Unstructured.GetKind(), msg.Trace.Unstructured.GetName()),
-		SideTitle: msg.Trace.Unstructured.GetAPIVersion(),
-		Content: m.styles.Main.Render(lipgloss.JoinVertical(
-			lipgloss.Top,
-			m.renderHealth("synced", msg.Trace.GetCondition(xpv1.TypeSynced)),
-			m.renderHealth("ready", msg.Trace.GetCondition(xpv1.TypeReady)),
-			m.renderMetadata(msg.Trace.Unstructured.GetAnnotations()),
-			hr,
-			string(val),
-		)),
-	})
+	m.input = msg
+	m.runQuery()
+	m.renderKeepingPosition(true)
 }
 
-func (m Model) renderHealth(name string, c xpv1.Condition) string {
-	info := []string{}
-	s := m.styles.BadHealth
-	n := lo.Capitalize(name)
-	if c.Status == k8sv1.ConditionTrue {
-		s = m.styles.OkHealth
+// runQuery evaluates the last query against the current resource
+func (m *Model) runQuery() {
+	m.queryResult, m.queryErr = "", nil
+	if len(m.queries) == 0 || m.input.Trace == nil {
+		return
 	}
+	m.queryResult, m.queryErr = xplane.Query(m.input.Trace.Unstructured.Object, m.queries[len(m.queries)-1])
+}
+
+func (m *Model) render() { m.renderKeepingPosition(false) }
 
-	if c.Reason == "" {
-		info = append(info, s.Render(fmt.Sprintf("%s: %s", n, c.Status)))
-	} else {
-		info = append(info, s.Render(fmt.Sprintf("%s: %s (%s)", n, c.Status, c.Reason)))
+func (m *Model) renderKeepingPosition(keep bool) {
+	if m.input.Trace == nil {
+		return
 	}
 
-	if c.Message != "" {
-		info = append(info, m.styles.Idented.Render(fmt.Sprintf("Message: %s", c.Message)))
+	var content string
+	switch m.tab {
+	case TabOverview:
+		content = m.renderOverview()
+	case TabConditions:
+		content = m.renderConditions()
+	case TabYAML:
+		content = m.renderYAML()
+	case TabEvents:
+		content = m.renderEvents()
+	case TabRelations:
+		content = m.renderRelations()
+	case TabQuery:
+		content = m.renderQuery()
+	case TabDiff:
+		content = m.renderDiff()
 	}
 
-	info = append(info, m.styles.Idented.Render(fmt.Sprintf("Last Transition Time: %s", c.LastTransitionTime.Format(time.RFC822))))
-	return lipgloss.JoinVertical(lipgloss.Top, info...)
+	m.viewer.SetContent(viewer.ContentInput{
+		Title:        fmt.Sprintf("%s/%s", m.input.Trace.Unstructured.GetKind(), m.input.Trace.Unstructured.GetName()),
+		SideTitle:    m.input.Trace.Unstructured.GetAPIVersion(),
+		Content:      m.styles.Main.Render(content),
+		KeepPosition: keep,
+	})
 }
 
-func (m Model) renderMetadata(annotations map[string]string) string {
-	info := []string{}
-	info = append(info, m.styles.Metadata.Render("Annotations:"))
-	for k, v := range annotations {
-		info = append(info, m.styles.Idented.Render(fmt.Sprintf("%s: \"%s\"", k, v)))
+func (m Model) tabsView() string {
+	// The query prompt takes over the tab bar while a query is being typed
+	if m.querying {
+		return m.styles.Tabs.Render(m.query.View())
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Top, info...)
+	visible := m.visibleTabs()
+	names := make([]string, 0, len(visible))
+	for _, t := range visible {
+		s := m.styles.Tab
+		if t == m.tab {
+			s = m.styles.ActiveTab
+		}
+		names = append(names, s.Render(t.String()))
+	}
+	return m.styles.Tabs.Render(strings.Join(names, " "))
+}
+
+// visibleTabs returns the tabs available for the described resource
+func (m Model) visibleTabs() []Tab {
+	if m.input.Comparison == nil {
+		return tabs
+	}
+	return append(slices.Clone(tabs), TabDiff)
 }
@@ -11,6 +11,14 @@ type Styles struct {
 	OkHealth  lipgloss.Style
 	BadHealth lipgloss.Style
 	Metadata  lipgloss.Style
+	Field     lipgloss.Style
+	Cell      lipgloss.Style
+	Border    lipgloss.Style
+	Hint      lipgloss.Style
+
+	Tabs      lipgloss.Style
+	Tab       lipgloss.Style
+	ActiveTab lipgloss.Style
 }
 
 func DefaultStyles() Styles {
@@ -20,5 +28,19 @@ func DefaultStyles() Styles {
 		OkHealth:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.ANSIColor(ansi.Green)),
 		BadHealth: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.ANSIColor(ansi.Red)),
 		Metadata:  lipgloss.NewStyle().Bold(true),
+		Field:     lipgloss.NewStyle().Bold(true).Width(28),
+		Cell:      lipgloss.NewStyle().Padding(0, 1),
+		Border:    lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.BrightBlack)),
+		Hint:      lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(ansi.BrightBlack)),
+
+		Tabs: lipgloss.NewStyle().Margin(1, 0, 0, 1),
+		Tab: lipgloss.NewStyle().
+			Padding(0, 1).
+			Foreground(lipgloss.ANSIColor(ansi.BrightBlack)),
+		ActiveTab: lipgloss.NewStyle().
+			Padding(0, 1).
+			Bold(true).
+			Background(lipgloss.ANSIColor(ansi.Blue)).
+			Foreground(lipgloss.ANSIColor(ansi.Black)),
 	}
 }
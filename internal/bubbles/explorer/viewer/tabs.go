@@ -0,0 +1,339 @@
+package viewer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/goccy/go-yaml"
+	"github.com/samber/lo"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+func (m Model) renderOverview() string {
+	obj := m.input.Trace.Unstructured
+
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "-"
+	}
+
+	owners := []string{}
+	for _, o := range obj.GetOwnerReferences() {
+		owner := fmt.Sprintf("%s/%s", o.Kind, o.Name)
+		if o.Controller != nil && *o.Controller {
+			owner += " (controller)"
+		}
+		owners = append(owners, owner)
+	}
+	if len(owners) == 0 {
+		owners = append(owners, "-")
+	}
+
+	age := "-"
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		age = duration.HumanDuration(time.Since(created.Time))
+	}
+
+	resourceName := obj.GetAnnotations()["crossplane.io/composition-resource-name"]
+	if resourceName == "" {
+		resourceName = "-"
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Top,
+		m.renderField("Kind", obj.GetKind()),
+		m.renderField("API Version", obj.GetAPIVersion()),
+		m.renderField("Name", obj.GetName()),
+		m.renderField("Namespace", namespace),
+		m.renderField("Age", age),
+		m.renderField("Owner", lipgloss.JoinVertical(lipgloss.Top, owners...)),
+		m.renderField("Composition Resource Name", resourceName),
+		"",
+		m.renderHealth("synced", m.input.Trace.GetCondition(xpv1.TypeSynced)),
+		m.renderHealth("ready", m.input.Trace.GetCondition(xpv1.TypeReady)),
+		"",
+		m.renderMetadata("Annotations", obj.GetAnnotations()),
+		m.renderMetadata("Labels", obj.GetLabels()),
+	)
+}
+
+func (m Model) renderConditions() string {
+	conditions := m.input.Trace.GetConditions()
+	if len(conditions) == 0 {
+		return "No conditions reported"
+	}
+
+	t := m.newTable().
+		Headers("TYPE", "STATUS", "REASON", "LAST TRANSITION", "MESSAGE").
+		StyleFunc(func(row, col int) lipgloss.Style {
+			s := m.styles.Cell
+			if row == table.HeaderRow {
+				return s.Inherit(m.styles.Metadata)
+			}
+			if col == 1 {
+				if conditions[row].Status == k8sv1.ConditionTrue {
+					return s.Inherit(m.styles.OkHealth)
+				}
+				return s.Inherit(m.styles.BadHealth)
+			}
+			return s
+		})
+
+	for _, c := range conditions {
+		t.Row(
+			string(c.Type),
+			string(c.Status),
+			string(c.Reason),
+			c.LastTransitionTime.Format(time.RFC822),
+			c.Message,
+		)
+	}
+
+	return t.Render()
+}
+
+func (m Model) renderYAML() string {
+	hint := "# raw object, press c to hide noisy fields"
+	if !m.rawYAML {
+		hint = "# noisy fields hidden, press c to show the raw object"
+	}
+
+	val, err := m.marshalYAML(m.input.Trace)
+	if err != nil {
+		return m.styles.BadHealth.Render(fmt.Sprintf("Failed to render YAML: %s", err))
+	}
+
+	if len(m.yamlFilters) == 0 {
+		return string(val)
+	}
+	return lipgloss.JoinVertical(lipgloss.Top, m.styles.Hint.Render(hint), string(val))
+}
+
+// marshalYAML renders an object as YAML, without noisy fields unless the raw
+// object was asked for. Missing resources are rendered as nothing.
+func (m Model) marshalYAML(r *xplane.Resource) ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	obj := r.Unstructured.Object
+	if !m.rawYAML {
+		for _, f := range m.yamlFilters {
+			obj = f(obj)
+		}
+	}
+	return yaml.Marshal(obj)
+}
+
+func (m Model) renderDiff() string {
+	c := m.input.Comparison
+	if c == nil {
+		return "Nothing to compare"
+	}
+
+	left, err := m.marshalYAML(c.Left)
+	if err != nil {
+		return m.styles.BadHealth.Render(fmt.Sprintf("Failed to render YAML: %s", err))
+	}
+	right, err := m.marshalYAML(c.Right)
+	if err != nil {
+		return m.styles.BadHealth.Render(fmt.Sprintf("Failed to render YAML: %s", err))
+	}
+
+	hint := fmt.Sprintf("# - %s, + %s", m.diffLabels[0], m.diffLabels[1])
+	switch {
+	case c.Left == nil:
+		hint += fmt.Sprintf(" (only found in %s)", m.diffLabels[1])
+	case c.Right == nil:
+		hint += fmt.Sprintf(" (only found in %s)", m.diffLabels[0])
+	}
+	if len(m.yamlFilters) > 0 {
+		hint += ", press c to toggle noisy fields"
+	}
+
+	lines := []string{m.styles.Hint.Render(hint)}
+	for _, l := range xplane.DiffLines(string(left), string(right)) {
+		switch l.Op {
+		case xplane.LineRemoved:
+			lines = append(lines, m.styles.BadHealth.Render("- "+l.Text))
+		case xplane.LineAdded:
+			lines = append(lines, m.styles.OkHealth.Render("+ "+l.Text))
+		default:
+			lines = append(lines, "  "+l.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderEvents() string {
+	switch {
+	case m.eventsErr != nil:
+		return m.styles.BadHealth.Render(fmt.Sprintf("Failed to load events: %s", m.eventsErr))
+	case !m.eventsLoaded:
+		return "Loading events..."
+	case len(m.events) == 0:
+		return "No events found"
+	}
+
+	events := m.events
+	t := m.newTable().
+		Headers("LAST SEEN", "TYPE", "REASON", "COUNT", "MESSAGE").
+		StyleFunc(func(row, col int) lipgloss.Style {
+			s := m.styles.Cell
+			if row == table.HeaderRow {
+				return s.Inherit(m.styles.Metadata)
+			}
+			if col == 1 && events[row].Type == k8sv1.EventTypeWarning {
+				return s.Inherit(m.styles.BadHealth)
+			}
+			return s
+		})
+
+	for _, e := range events {
+		lastSeen := "-"
+		if ts := eventTime(e); !ts.IsZero() {
+			lastSeen = duration.HumanDuration(time.Since(ts))
+		}
+		t.Row(lastSeen, e.Type, e.Reason, fmt.Sprintf("%d", e.Count), e.Message)
+	}
+
+	return t.Render()
+}
+
+func (m Model) renderRelations() string {
+	info := []string{m.styles.Metadata.Render("Parent:")}
+	if m.input.Parent == nil {
+		info = append(info, m.styles.Idented.Render("-"))
+	} else {
+		info = append(info, m.renderRelation(m.input.Parent))
+	}
+
+	info = append(info, "", m.styles.Metadata.Render("Children:"))
+	if len(m.input.Trace.Children) == 0 {
+		info = append(info, m.styles.Idented.Render("-"))
+	}
+	for _, c := range m.input.Trace.Children {
+		info = append(info, m.renderRelation(c))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top, info...)
+}
+
+func (m Model) renderRelation(r *xplane.Resource) string {
+	name := fmt.Sprintf("%s/%s", r.Unstructured.GetKind(), r.Unstructured.GetName())
+	status := xplane.GetResourceStatus(r, name)
+
+	s := m.styles.BadHealth
+	if status.Ok {
+		s = m.styles.OkHealth
+	}
+
+	return m.styles.Idented.Render(fmt.Sprintf(
+		"%s (%s) %s",
+		name,
+		r.Unstructured.GroupVersionKind().Group,
+		s.Render(fmt.Sprintf("Synced=%s Ready=%s", status.Synced, status.Ready)),
+	))
+}
+
+func (m Model) renderQuery() string {
+	if len(m.queries) == 0 {
+		return lipgloss.JoinVertical(
+			lipgloss.Top,
+			"Press : to query the object using JSONPath or jq-like paths, such as:",
+			m.styles.Idented.Render("{.status.atProvider.arn}"),
+			m.styles.Idented.Render(`.metadata.annotations["crossplane.io/composition-resource-name"]`),
+			m.styles.Idented.Render(".status.conditions[].reason"),
+		)
+	}
+
+	result := m.queryResult
+	if m.queryErr != nil {
+		result = m.styles.BadHealth.Render(m.queryErr.Error())
+	}
+
+	history := []string{m.styles.Metadata.Render("History:")}
+	for i := len(m.queries) - 1; i >= 0; i-- {
+		history = append(history, m.styles.Idented.Render(m.queries[i]))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Top,
+		m.renderField("Query", m.queries[len(m.queries)-1]),
+		m.styles.Hint.Render("press y to yank the result"),
+		"",
+		result,
+		"",
+		lipgloss.JoinVertical(lipgloss.Top, history...),
+	)
+}
+
+func (m Model) renderField(name, value string) string {
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		m.styles.Field.Render(name+":"),
+		value,
+	)
+}
+
+func (m Model) renderHealth(name string, c xpv1.Condition) string {
+	info := []string{}
+	s := m.styles.BadHealth
+	n := lo.Capitalize(name)
+	if c.Status == k8sv1.ConditionTrue {
+		s = m.styles.OkHealth
+	}
+
+	if c.Reason == "" {
+		info = append(info, s.Render(fmt.Sprintf("%s: %s", n, c.Status)))
+	} else {
+		info = append(info, s.Render(fmt.Sprintf("%s: %s (%s)", n, c.Status, c.Reason)))
+	}
+
+	if c.Message != "" {
+		info = append(info, m.styles.Idented.Render(fmt.Sprintf("Message: %s", c.Message)))
+	}
+
+	info = append(info, m.styles.Idented.Render(fmt.Sprintf("Last Transition Time: %s", c.LastTransitionTime.Format(time.RFC822))))
+	return lipgloss.JoinVertical(lipgloss.Top, info...)
+}
+
+func (m Model) renderMetadata(title string, values map[string]string) string {
+	info := []string{}
+	info = append(info, m.styles.Metadata.Render(title+":"))
+	for _, k := range lo.Keys(values) {
+		info = append(info, m.styles.Idented.Render(fmt.Sprintf("%s: \"%s\"", k, values[k])))
+	}
+	sort.Strings(info[1:])
+
+	return lipgloss.JoinVertical(lipgloss.Top, info...)
+}
+
+func (m Model) newTable() *table.Table {
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(m.styles.Border)
+	if w := m.viewer.GetWidth(); w > 4 {
+		t = t.Width(w - 4)
+	}
+	return t
+}
+
+// eventTime returns the most recent timestamp recorded in an event
+func eventTime(e k8sv1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.FirstTimestamp.Time
+	}
+}
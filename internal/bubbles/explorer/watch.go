@@ -0,0 +1,160 @@
+package explorer
+
+import (
+	"slices"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/explorer/statusbar"
+	"github.com/brunoluiz/crossplane-explorer/internal/tasker"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxRetryBackoff caps the time between retries of a failing watch
+const maxRetryBackoff = 2 * time.Minute
+
+// watchIntervals are the steps used when changing the interval at runtime
+var watchIntervals = []time.Duration{
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+}
+
+// traceErrMsg is sent when getting a trace fails
+type traceErrMsg struct{ err error }
+
+// tickMsg triggers a refresh, unless a more recent tick superseded it
+type tickMsg struct{ id int }
+
+// clockMsg is sent every second, keeping time based indicators up to date
+type clockMsg struct{}
+
+func clock() tea.Cmd {
+	return tea.Tick(time.Second, func(_ time.Time) tea.Msg { return clockMsg{} })
+}
+
+// refresh starts a trace, unless there is one in flight already. Pending
+// ticks are superseded, so there is only one refresh going on at a time.
+func (m *Model) refresh() tea.Cmd {
+	if m.tracing {
+		return nil
+	}
+
+	m.tickID++
+	m.tracing = true
+	return tea.Batch(m.getTrace(), m.watchStatus())
+}
+
+// schedule a refresh, superseding any pending tick
+func (m *Model) schedule(wait time.Duration) tea.Cmd {
+	m.tickID++
+	id := m.tickID
+	return tea.Tick(wait, func(_ time.Time) tea.Msg { return tickMsg{id: id} })
+}
+
+func (m *Model) onTick(msg tickMsg) tea.Cmd {
+	if msg.id != m.tickID || !m.watch {
+		return nil
+	}
+	return m.refresh()
+}
+
+// onRefreshed is called after every trace, successful or not, and schedules
+// the next one if watching
+func (m *Model) onRefreshed(err error) tea.Cmd {
+	m.tracing = false
+	m.lastErr = err
+
+	if err == nil {
+		m.failures = 0
+		m.lastRefresh = time.Now()
+		if !m.watch {
+			return m.watchStatus()
+		}
+		return tea.Batch(m.schedule(m.watchInterval), m.watchStatus())
+	}
+
+	m.failures++
+	if !m.watch {
+		m.retryAt = time.Time{}
+		return m.watchStatus()
+	}
+
+	wait := tasker.Backoff(m.watchInterval, maxRetryBackoff, m.failures)
+	m.retryAt = time.Now().Add(wait)
+	m.logger.Warn("failed to refresh trace", "err", err, "failures", m.failures, "retry_in", wait)
+	return tea.Batch(m.schedule(wait), m.watchStatus())
+}
+
+// onTraceErr keeps the last good trace around and retries with an exponential
+// backoff. Errors are only fatal if there is nothing to show.
+func (m *Model) onTraceErr(err error) tea.Cmd {
+	if !m.loaded {
+		m.err = err
+		return nil
+	}
+	return m.onRefreshed(err)
+}
+
+func (m *Model) onWatchKey(k string) tea.Cmd {
+	switch k {
+	case "r":
+		return m.refresh()
+	case "p":
+		m.watch = !m.watch
+		m.paused = !m.watch
+		if !m.watch {
+			m.stopLive()
+			m.tickID++ // drops the pending tick
+			return m.watchStatus()
+		}
+		return m.refresh()
+	case "+", "-":
+		m.watchInterval = stepInterval(m.watchInterval, k == "+")
+
+		if !m.watch || m.tracing || m.lastErr != nil || m.liveActive {
+			return m.watchStatus()
+		}
+		return tea.Batch(m.schedule(m.watchInterval), m.watchStatus())
+	}
+	return nil
+}
+
+// stepInterval returns the closest step above the interval, or below it if
+// not going up. Intervals beyond the steps are kept as they are.
+func stepInterval(d time.Duration, up bool) time.Duration {
+	if up {
+		for _, i := range watchIntervals {
+			if i > d {
+				return i
+			}
+		}
+		return d
+	}
+
+	for _, i := range slices.Backward(watchIntervals) {
+		if i < d {
+			return i
+		}
+	}
+	return d
+}
+
+func (m *Model) watchStatus() tea.Cmd {
+	msg := statusbar.WatchStatusMsg{
+		Enabled:     m.watch,
+		Paused:      m.paused,
+		Live:        m.liveActive,
+		Interval:    m.watchInterval,
+		Refreshing:  m.tracing,
+		LastRefresh: m.lastRefresh,
+		Err:         m.lastErr,
+		RetryAt:     m.retryAt,
+	}
+	return func() tea.Msg { return msg }
+}
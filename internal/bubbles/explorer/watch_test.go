@@ -0,0 +1,70 @@
+package explorer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStepInterval(t *testing.T) {
+	type args struct {
+		interval time.Duration
+		up       bool
+	}
+
+	type want struct {
+		interval time.Duration
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpFromStep": {
+			reason: "Should go up to the next step",
+			args:   args{interval: 5 * time.Second, up: true},
+			want:   want{interval: 10 * time.Second},
+		},
+		"DownFromStep": {
+			reason: "Should go down to the previous step",
+			args:   args{interval: 5 * time.Second},
+			want:   want{interval: 2 * time.Second},
+		},
+		"UpBetweenSteps": {
+			reason: "Should go up to the closest step above an interval between steps",
+			args:   args{interval: 3 * time.Second, up: true},
+			want:   want{interval: 5 * time.Second},
+		},
+		"DownBetweenSteps": {
+			reason: "Should go down to the closest step below an interval between steps",
+			args:   args{interval: 45 * time.Second},
+			want:   want{interval: 30 * time.Second},
+		},
+		"UpFromLast": {
+			reason: "Should keep the last step when going up",
+			args:   args{interval: 5 * time.Minute, up: true},
+			want:   want{interval: 5 * time.Minute},
+		},
+		"DownFromFirst": {
+			reason: "Should keep the first step when going down",
+			args:   args{interval: time.Second},
+			want:   want{interval: time.Second},
+		},
+		"UpBeyondSteps": {
+			reason: "Should keep intervals above all steps when going up",
+			args:   args{interval: 10 * time.Minute, up: true},
+			want:   want{interval: 10 * time.Minute},
+		},
+		"DownBeyondSteps": {
+			reason: "Should go down to the last step from intervals above all steps",
+			args:   args{interval: 10 * time.Minute},
+			want:   want{interval: 5 * time.Minute},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := stepInterval(tc.args.interval, tc.args.up); got != tc.want.interval {
+				t.Errorf("%s\nstepInterval(%s, %v) = %s, want %s", tc.reason, tc.args.interval, tc.args.up, got, tc.want.interval)
+			}
+		})
+	}
+}
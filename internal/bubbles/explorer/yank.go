@@ -0,0 +1,87 @@
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/menu"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/goccy/go-yaml"
+)
+
+type yankFormat struct {
+	item   menu.Item
+	format func(r *xplane.Resource) (string, error)
+}
+
+var yankFormats = []yankFormat{
+	{item: menu.Item{Key: "y", Label: "kind.group/name"}, format: yankFullName},
+	{item: menu.Item{Key: "n", Label: "name"}, format: yankName},
+	{item: menu.Item{Key: "s", Label: "namespace/name"}, format: yankNamespacedName},
+	{item: menu.Item{Key: "m", Label: "yaml"}, format: yankYAML},
+	{item: menu.Item{Key: "j", Label: "json"}, format: yankJSON},
+	{item: menu.Item{Key: "g", Label: "kubectl get"}, format: yankKubectl("get")},
+	{item: menu.Item{Key: "d", Label: "kubectl describe"}, format: yankKubectl("describe")},
+}
+
+func newYankMenu() menu.Model {
+	items := make([]menu.Item, 0, len(yankFormats))
+	for _, f := range yankFormats {
+		items = append(items, f.item)
+	}
+	return menu.New("yank", items)
+}
+
+func yankFullName(r *xplane.Resource) (string, error) {
+	return fmt.Sprintf(
+		"%s.%s/%s",
+		r.Unstructured.GetKind(),
+		r.Unstructured.GroupVersionKind().Group,
+		r.Unstructured.GetName(),
+	), nil
+}
+
+func yankName(r *xplane.Resource) (string, error) {
+	return r.Unstructured.GetName(), nil
+}
+
+func yankNamespacedName(r *xplane.Resource) (string, error) {
+	if ns := r.Unstructured.GetNamespace(); ns != "" {
+		return fmt.Sprintf("%s/%s", ns, r.Unstructured.GetName()), nil
+	}
+	return r.Unstructured.GetName(), nil
+}
+
+func yankYAML(r *xplane.Resource) (string, error) {
+	out, err := yaml.Marshal(r.Unstructured.Object)
+	return string(out), err
+}
+
+func yankJSON(r *xplane.Resource) (string, error) {
+	out, err := json.MarshalIndent(r.Unstructured.Object, "", "  ")
+	return string(out), err
+}
+
+func yankKubectl(verb string) func(r *xplane.Resource) (string, error) {
+	return func(r *xplane.Resource) (string, error) {
+		args := []string{"kubectl", verb, resourceType(r), r.Unstructured.GetName()}
+		if ns := r.Unstructured.GetNamespace(); ns != "" {
+			args = append(args, "--namespace", ns)
+		}
+		if verb == "get" {
+			args = append(args, "-o", "yaml")
+		}
+		return strings.Join(args, " "), nil
+	}
+}
+
+// resourceType returns the fully qualified type used by kubectl, such as
+// `bucket.s3.aws.upbound.io`
+func resourceType(r *xplane.Resource) string {
+	kind := strings.ToLower(r.Unstructured.GetKind())
+	if group := r.Unstructured.GroupVersionKind().Group; group != "" {
+		return kind + "." + group
+	}
+	return kind
+}
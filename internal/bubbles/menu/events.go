@@ -0,0 +1,36 @@
+package menu
+
+import tea "github.com/charmbracelet/bubbletea"
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tea.KeyMsg:
+		if m.open {
+			cmd = m.onKey(msg)
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	k := msg.String()
+	if k == "esc" || k == "q" {
+		m.open = false
+		return nil
+	}
+
+	for _, it := range m.items {
+		if it.Key == k {
+			m.open = false
+			return func() tea.Msg {
+				return SelectedMsg{Title: m.title, Item: it}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Item is an entry in the menu, selected by pressing its key
+type Item struct {
+	Key   string
+	Label string
+}
+
+// SelectedMsg is emitted when an item gets selected
+type SelectedMsg struct {
+	Title string
+	Item  Item
+}
+
+// Model is a single line menu, meant to replace a statusbar while open
+type Model struct {
+	title  string
+	items  []Item
+	open   bool
+	width  int
+	styles Styles
+}
+
+func New(title string, items []Item) Model {
+	return Model{
+		title:  title,
+		items:  items,
+		styles: DefaultStyles(),
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+func (m Model) View() string {
+	items := make([]string, 0, len(m.items))
+	for _, it := range m.items {
+		items = append(items, fmt.Sprintf("%s %s", m.styles.Key.Render(it.Key), it.Label))
+	}
+
+	title := m.styles.Title.Render(m.title)
+	w := max(m.width-lipgloss.Width(title), 0)
+	return title + m.styles.Items.
+		Width(w).
+		Render(ansi.Truncate(strings.Join(items, "  "), w-m.styles.Items.GetHorizontalFrameSize(), "…"))
+}
+
+func (m Model) IsOpen() bool { return m.open }
+
+func (m *Model) Open()  { m.open = true }
+func (m *Model) Close() { m.open = false }
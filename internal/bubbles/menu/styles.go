@@ -0,0 +1,30 @@
+package menu
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type Styles struct {
+	Title lipgloss.Style
+	Items lipgloss.Style
+	Key   lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Padding(0, 1).
+			Bold(true).
+			Background(lipgloss.ANSIColor(ansi.Magenta)).
+			Foreground(lipgloss.ANSIColor(ansi.White)),
+		Items: lipgloss.NewStyle().
+			Padding(0, 1).
+			Background(lipgloss.ANSIColor(ansi.BrightBlack)).
+			Foreground(lipgloss.ANSIColor(ansi.White)),
+		Key: lipgloss.NewStyle().
+			Bold(true).
+			Background(lipgloss.ANSIColor(ansi.BrightBlack)).
+			Foreground(lipgloss.ANSIColor(ansi.Yellow)),
+	}
+}
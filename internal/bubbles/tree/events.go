@@ -53,6 +53,44 @@ func (m *Model) onNavDown() {
 	m.onSelectionChange(m.nodesByCursor[m.cursor])
 }
 
+// onCollapse collapses the current node or, if it is already collapsed (or
+// has no children), moves the cursor to its parent
+func (m *Model) onCollapse() {
+	n := m.Current()
+	if n == nil {
+		return
+	}
+
+	if len(n.Children) > 0 && !m.collapsed[n.id()] {
+		m.collapsed[n.id()] = true
+		m.render()
+		m.setCursor(m.cursor)
+		return
+	}
+
+	if p, ok := m.parents[n]; ok {
+		m.setCursor(m.cursorByNode[p])
+	}
+}
+
+// onExpand expands the current node or, if it is already expanded, moves the
+// cursor to its first child
+func (m *Model) onExpand() {
+	n := m.Current()
+	if n == nil || len(n.Children) == 0 {
+		return
+	}
+
+	if m.collapsed[n.id()] {
+		delete(m.collapsed, n.id())
+		m.render()
+		m.setCursor(m.cursor)
+		return
+	}
+
+	m.setCursor(m.cursor + 1)
+}
+
 func (m *Model) onSelectionChange(node *Node) {
 	if m.OnSelectionChange == nil {
 		return
@@ -66,6 +104,10 @@ func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
 		m.onNavUp()
 	case key.Matches(msg, m.KeyMap.Down):
 		m.onNavDown()
+	case key.Matches(msg, m.KeyMap.Collapse):
+		m.onCollapse()
+	case key.Matches(msg, m.KeyMap.Expand):
+		m.onExpand()
 	case key.Matches(msg, m.KeyMap.ShowFullHelp):
 		fallthrough
 	case key.Matches(msg, m.KeyMap.CloseFullHelp):
@@ -9,10 +9,21 @@ type KeyMap struct {
 	SectionUp   key.Binding
 	Down        key.Binding
 	Up          key.Binding
+	Collapse    key.Binding
+	Expand      key.Binding
 	Quit        key.Binding
 
 	Yank          key.Binding
 	Describe      key.Binding
+	Refresh       key.Binding
+	Pause         key.Binding
+	IntervalUp    key.Binding
+	IntervalDown  key.Binding
+	Timeline      key.Binding
+	Report        key.Binding
+	Bundle        key.Binding
+	GroupBy       key.Binding
+	Breadcrumb    key.Binding
 	ShowFullHelp  key.Binding
 	CloseFullHelp key.Binding
 }
@@ -44,6 +55,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("up", "k"),
 			key.WithHelp("↑/k", "up"),
 		),
+		Collapse: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "collapse"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "expand"),
+		),
 
 		Yank: key.NewBinding(
 			key.WithKeys("y"),
@@ -53,6 +72,42 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter", "d"),
 			key.WithHelp("enter/d", "describe"),
 		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause/resume watch"),
+		),
+		IntervalUp: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "slower watch"),
+		),
+		IntervalDown: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "faster watch"),
+		),
+		Timeline: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "timeline"),
+		),
+		Report: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "provisioning report"),
+		),
+		GroupBy: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "group by"),
+		),
+		Breadcrumb: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "breadcrumb"),
+		),
+		Bundle: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "support bundle"),
+		),
 		ShowFullHelp: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -24,6 +25,9 @@ type ColorConfig struct {
 }
 
 type Node struct {
+	// ID identifies a node across calls to SetNodes, keeping the cursor and
+	// collapsed state attached to it. Key is used if it is not set.
+	ID      string
 	Key     string
 	Value   string
 	Details map[string]string
@@ -48,6 +52,9 @@ type Model struct {
 	height        int
 	nodes         []*Node
 	nodesByCursor map[int]*Node
+	cursorByNode  map[*Node]int
+	parents       map[*Node]*Node
+	collapsed     map[string]bool
 	cursor        int
 
 	showHelp bool
@@ -62,6 +69,9 @@ func New(t table.Model) Model {
 		width:         0,
 		height:        0,
 		nodesByCursor: map[int]*Node{},
+		cursorByNode:  map[*Node]int{},
+		parents:       map[*Node]*Node{},
+		collapsed:     map[string]bool{},
 
 		showHelp: true,
 		Help:     help.New(),
@@ -84,28 +94,93 @@ func (m Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, m.table.View(), help)
 }
 
+// SetNodes replaces the tree nodes. The cursor sticks to the previously
+// selected node (matched by ID) or, if it is gone, to its nearest ancestor.
 func (m *Model) SetNodes(nodes []*Node) tea.Cmd {
+	var selected []string
+	for n := m.Current(); n != nil; n = m.parents[n] {
+		selected = append(selected, n.id())
+	}
+
 	m.nodes = nodes
+	m.render()
+	m.restoreCursor(selected)
+	m.table.Focus()
+
+	return nil
+}
+
+// render rebuilds the table rows for all visible nodes
+func (m *Model) render() {
+	m.nodesByCursor = map[int]*Node{}
+	m.cursorByNode = map[*Node]int{}
+	m.parents = map[*Node]*Node{}
 
 	count := 0 // This is used to keep track of the index of the node we are on (important because we are using a recursive function)
 	rows := []table.Row{}
-	m.renderTree(&rows, m.nodes, []string{}, 0, &count)
+	m.renderTree(&rows, m.nodes, nil, []string{}, 0, &count)
 	m.table.SetRows(rows)
-	m.table.Focus()
+}
 
-	return nil
+// restoreCursor moves the cursor to the first visible node out of a list of
+// IDs, usually a previously selected node followed by its ancestors
+func (m *Model) restoreCursor(ids []string) {
+	for _, id := range ids {
+		for idx := range m.numberOfNodes() {
+			if m.nodesByCursor[idx].id() == id {
+				m.setCursor(idx)
+				return
+			}
+		}
+	}
+	m.setCursor(m.cursor)
+}
+
+func (m *Model) setCursor(idx int) {
+	m.cursor = max(min(idx, m.numberOfNodes()-1), 0)
+
+	// Move through the table so it keeps the cursor within its viewport
+	switch delta := m.cursor - m.table.Cursor(); {
+	case delta > 0:
+		m.table.MoveDown(delta)
+	case delta < 0:
+		m.table.MoveUp(-delta)
+	default:
+		m.table.SetCursor(m.cursor)
+	}
+
+	if n := m.Current(); n != nil {
+		m.onSelectionChange(n)
+	}
+}
+
+// SelectAncestor moves the cursor to the ancestor of the current node the
+// given number of levels up, stopping at the root
+func (m *Model) SelectAncestor(levels int) {
+	n := m.Current()
+	if n == nil {
+		return
+	}
+	for ; levels > 0 && m.parents[n] != nil; levels-- {
+		n = m.parents[n]
+	}
+	m.setCursor(m.cursorByNode[n])
 }
 
 func (m Model) ShortHelp() []key.Binding {
 	kb := []key.Binding{
 		m.KeyMap.Up,
 		m.KeyMap.Down,
+		m.KeyMap.Collapse,
+		m.KeyMap.Expand,
 		m.KeyMap.Yank,
 		m.KeyMap.Describe,
+		m.KeyMap.Refresh,
 	}
 
 	return append(kb,
 		m.KeyMap.Quit,
+		m.KeyMap.ShowFullHelp,
 	)
 }
 
@@ -113,8 +188,21 @@ func (m Model) FullHelp() [][]key.Binding {
 	kb := [][]key.Binding{{
 		m.KeyMap.Up,
 		m.KeyMap.Down,
+		m.KeyMap.Collapse,
+		m.KeyMap.Expand,
 		m.KeyMap.Yank,
 		m.KeyMap.Describe,
+	}, {
+		m.KeyMap.Refresh,
+		m.KeyMap.Pause,
+		m.KeyMap.IntervalUp,
+		m.KeyMap.IntervalDown,
+	}, {
+		m.KeyMap.Timeline,
+		m.KeyMap.Report,
+		m.KeyMap.GroupBy,
+		m.KeyMap.Breadcrumb,
+		m.KeyMap.Bundle,
 	}}
 
 	return append(kb,
@@ -128,25 +216,25 @@ func (m Model) Current() *Node             { return m.nodesByCursor[m.cursor] }
 func (m *Model) SetShowHelp() bool         { return m.showHelp }
 func (m *Model) setSize(width, height int) { m.width = width; m.height = height }
 
-func (m *Model) numberOfNodes() int {
-	count := 0
+// numberOfNodes returns the number of visible nodes
+func (m *Model) numberOfNodes() int { return len(m.nodesByCursor) }
 
-	var countNodes func([]*Node)
-	countNodes = func(nodes []*Node) {
-		for _, node := range nodes {
-			count++
-			if node.Children != nil {
-				countNodes(node.Children)
-			}
-		}
+func (n *Node) id() string {
+	if n.ID != "" {
+		return n.ID
 	}
+	return n.Key
+}
 
-	countNodes(m.nodes)
-
+func countDescendants(n *Node) int {
+	count := len(n.Children)
+	for _, c := range n.Children {
+		count += countDescendants(c)
+	}
 	return count
 }
 
-func (m *Model) renderTree(rows *[]table.Row, remainingNodes []*Node, path []string, indent int, count *int) {
+func (m *Model) renderTree(rows *[]table.Row, remainingNodes []*Node, parent *Node, path []string, indent int, count *int) {
 	const treeNodePrefix string = " └─"
 
 	for _, node := range remainingNodes {
@@ -165,20 +253,30 @@ func (m *Model) renderTree(rows *[]table.Row, remainingNodes []*Node, path []str
 			s = s.Foreground(node.Color)
 		}
 
-		cols := []table.Cell{{Value: shape + node.Key, Style: s}}
+		collapsed := len(node.Children) > 0 && m.collapsed[node.id()]
+		key := node.Key
+		if collapsed {
+			key = fmt.Sprintf("%s (+%d)", key, countDescendants(node))
+		}
+
+		cols := []table.Cell{{Value: shape + key, Style: s}}
 		for _, v := range m.table.Columns()[1:] {
 			cols = append(cols, table.Cell{Value: node.Details[v.Title], Style: s})
 		}
 
 		*rows = append(*rows, cols)
 		m.nodesByCursor[idx] = node
+		m.cursorByNode[node] = idx
+		if parent != nil {
+			m.parents[node] = parent
+		}
 
 		// Used to be able to trace back the path on the tree
 		node.Path = path
 		node.Path = append(node.Path, node.Key)
 
-		if node.Children != nil {
-			m.renderTree(rows, node.Children, node.Path, indent+1, count)
+		if node.Children != nil && !collapsed {
+			m.renderTree(rows, node.Children, node, node.Path, indent+1, count)
 		}
 	}
 }
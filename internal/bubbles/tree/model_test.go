@@ -0,0 +1,140 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newNodes returns a fresh root{a{a1}, b} tree, without a1 if it is gone
+func newNodes(withA1 bool) []*Node {
+	a := &Node{ID: "a", Key: "a"}
+	if withA1 {
+		a.Children = []*Node{{ID: "a1", Key: "a1"}}
+	}
+	return []*Node{{ID: "root", Key: "root", Children: []*Node{a, {ID: "b", Key: "b"}}}}
+}
+
+func newModel(nodes []*Node, keys ...tea.KeyType) Model {
+	m := New(table.New(table.WithColumns([]table.Column{{Title: "NAME", Width: 10}})))
+	m.SetNodes(nodes)
+	for _, k := range keys {
+		m, _ = m.Update(tea.KeyMsg{Type: k})
+	}
+	return m
+}
+
+func TestSetNodes(t *testing.T) {
+	type args struct {
+		nodes []*Node
+		keys  []tea.KeyType
+		next  []*Node
+	}
+
+	type want struct {
+		current string
+		visible int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"KeepSelection": {
+			reason: "Should keep the cursor on the node with the same ID",
+			args: args{
+				nodes: newNodes(true),
+				keys:  []tea.KeyType{tea.KeyDown, tea.KeyDown},
+				next:  newNodes(true),
+			},
+			want: want{current: "a1", visible: 4},
+		},
+		"FallbackToAncestor": {
+			reason: "Should move the cursor to the nearest ancestor of a node which is gone",
+			args: args{
+				nodes: newNodes(true),
+				keys:  []tea.KeyType{tea.KeyDown, tea.KeyDown},
+				next:  newNodes(false),
+			},
+			want: want{current: "a", visible: 3},
+		},
+		"KeepCollapsed": {
+			reason: "Should keep collapsed nodes collapsed",
+			args: args{
+				nodes: newNodes(true),
+				keys:  []tea.KeyType{tea.KeyDown, tea.KeyLeft},
+				next:  newNodes(true),
+			},
+			want: want{current: "a", visible: 3},
+		},
+		"DuplicateIDs": {
+			reason: "Should move the cursor to the first node sharing the selected ID",
+			args: args{
+				nodes: []*Node{{ID: "root", Children: []*Node{{ID: "dup", Key: "left"}, {ID: "dup", Key: "right"}}}},
+				keys:  []tea.KeyType{tea.KeyDown, tea.KeyDown},
+				next:  []*Node{{ID: "root", Children: []*Node{{ID: "dup", Key: "left"}, {ID: "dup", Key: "right"}}}},
+			},
+			want: want{current: "left", visible: 3},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newModel(tc.args.nodes, tc.args.keys...)
+			m.SetNodes(tc.args.next)
+
+			if got := m.Current().Key; got != tc.want.current {
+				t.Errorf("%s\nCurrent() = %q, want %q", tc.reason, got, tc.want.current)
+			}
+			if got := m.numberOfNodes(); got != tc.want.visible {
+				t.Errorf("%s\nnumberOfNodes() = %d, want %d", tc.reason, got, tc.want.visible)
+			}
+		})
+	}
+}
+
+func TestSelectAncestor(t *testing.T) {
+	type args struct {
+		levels int
+	}
+
+	type want struct {
+		current string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Self": {
+			reason: "Should keep the cursor in place for zero levels",
+			args:   args{levels: 0},
+			want:   want{current: "a1"},
+		},
+		"Parent": {
+			reason: "Should move the cursor to the parent",
+			args:   args{levels: 1},
+			want:   want{current: "a"},
+		},
+		"Root": {
+			reason: "Should move the cursor to the root",
+			args:   args{levels: 2},
+			want:   want{current: "root"},
+		},
+		"BeyondRoot": {
+			reason: "Should stop at the root",
+			args:   args{levels: 5},
+			want:   want{current: "root"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newModel(newNodes(true), tea.KeyDown, tea.KeyDown)
+			m.SelectAncestor(tc.args.levels)
+
+			if got := m.Current().Key; got != tc.want.current {
+				t.Errorf("%s\nSelectAncestor(%d) = %q, want %q", tc.reason, tc.args.levels, got, tc.want.current)
+			}
+		})
+	}
+}
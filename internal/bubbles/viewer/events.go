@@ -14,11 +14,22 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While typing a query, keys must not reach the viewport
+		if m.searching {
+			return m, m.onSearchKey(msg)
+		}
 		cmds = append(cmds, m.onKey(msg))
 	case tea.WindowSizeMsg:
 		cmds = append(cmds, m.onResize(msg))
 	}
 
+	// Keeps the search prompt cursor blinking. Keys are left out, otherwise
+	// the "/" which starts a search ends up in the query.
+	if _, ok := msg.(tea.KeyMsg); !ok && m.searching {
+		m.search, cmd = m.search.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	// Handle keyboard and mouse events in the viewport
 	m.viewport, cmd = m.viewport.Update(msg)
 	cmds = append(cmds, cmd)
@@ -27,12 +38,66 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
-	if k := msg.String(); k == "ctrl+c" || k == "q" || k == "esc" {
+	switch k := msg.String(); k {
+	case "ctrl+c", "q", "esc":
 		return m.cmdQuit
+	case "/":
+		m.searching = true
+		m.search.SetValue(m.query)
+		m.search.CursorEnd()
+		return m.search.Focus()
+	case "n":
+		m.onMatchNav(1)
+	case "N":
+		m.onMatchNav(-1)
 	}
 	return nil
 }
 
+func (m *Model) onSearchKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		m.searching = false
+		m.search.Blur()
+		return nil
+	case "esc":
+		m.searching = false
+		m.search.Blur()
+		m.query = ""
+		m.viewport.SetContent(m.highlight())
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	if m.query != m.search.Value() {
+		m.query = m.search.Value()
+		m.matchCursor = 0
+		m.viewport.SetContent(m.highlight())
+		m.scrollToMatch()
+	}
+	return cmd
+}
+
+// onMatchNav moves the current match by delta, wrapping around both ends
+func (m *Model) onMatchNav(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+
+	m.matchCursor = (m.matchCursor + delta + len(m.matches)) % len(m.matches)
+	m.viewport.SetContent(m.highlight())
+	m.scrollToMatch()
+}
+
+// scrollToMatch centres the viewport around the current match
+func (m *Model) scrollToMatch() {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.viewport.SetYOffset(m.matches[m.matchCursor] - m.viewport.Height/2)
+}
+
 func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
 	headerHeight := lipgloss.Height(m.headerView())
 	footerHeight := lipgloss.Height(m.footerView())
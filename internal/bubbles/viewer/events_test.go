@@ -0,0 +1,108 @@
+package viewer
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const content = `bucket: a
+role: b
+bucket: c
+policy: d
+bucket: e`
+
+func keys(s ...string) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, k := range s {
+		switch k {
+		case "enter":
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyEnter})
+		case "esc":
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyEsc})
+		default:
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+	return msgs
+}
+
+func TestSearch(t *testing.T) {
+	type args struct {
+		keys []tea.KeyMsg
+	}
+
+	type want struct {
+		query     string
+		searching bool
+		matches   int
+		cursor    int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Start": {
+			reason: "Should not type the key starting the search into the query",
+			args:   args{keys: keys("/")},
+			want:   want{searching: true},
+		},
+		"Type": {
+			reason: "Should search while typing",
+			args:   args{keys: keys("/", "b", "u", "c", "k", "e", "t")},
+			want:   want{query: "bucket", searching: true, matches: 3},
+		},
+		"Enter": {
+			reason: "Should keep the query after confirming it",
+			args:   args{keys: keys("/", "bucket", "enter")},
+			want:   want{query: "bucket", matches: 3},
+		},
+		"Next": {
+			reason: "Should move to the next match",
+			args:   args{keys: keys("/", "bucket", "enter", "n", "n")},
+			want:   want{query: "bucket", matches: 3, cursor: 2},
+		},
+		"NextWraps": {
+			reason: "Should wrap around to the first match",
+			args:   args{keys: keys("/", "bucket", "enter", "n", "n", "n")},
+			want:   want{query: "bucket", matches: 3, cursor: 0},
+		},
+		"PreviousWraps": {
+			reason: "Should wrap around to the last match",
+			args:   args{keys: keys("/", "bucket", "enter", "N")},
+			want:   want{query: "bucket", matches: 3, cursor: 2},
+		},
+		"Cancel": {
+			reason: "Should clear the query when cancelling the search",
+			args:   args{keys: keys("/", "bucket", "esc")},
+			want:   want{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New()
+			m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+			m.SetContent(ContentInput{Title: "bucket", Content: content})
+			for _, k := range tc.args.keys {
+				m, _ = m.Update(k)
+			}
+
+			if m.query != tc.want.query {
+				t.Errorf("%s\nquery = %q, want %q", tc.reason, m.query, tc.want.query)
+			}
+			if m.searching && m.search.Value() != tc.want.query {
+				t.Errorf("%s\nsearch.Value() = %q, want %q", tc.reason, m.search.Value(), tc.want.query)
+			}
+			if m.searching != tc.want.searching {
+				t.Errorf("%s\nsearching = %v, want %v", tc.reason, m.searching, tc.want.searching)
+			}
+			if len(m.matches) != tc.want.matches {
+				t.Errorf("%s\nmatches = %d, want %d", tc.reason, len(m.matches), tc.want.matches)
+			}
+			if m.matchCursor != tc.want.cursor {
+				t.Errorf("%s\nmatchCursor = %d, want %d", tc.reason, m.matchCursor, tc.want.cursor)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package viewer
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type Model struct {
@@ -26,6 +29,12 @@ type Model struct {
 
 	ready    bool
 	viewport viewport.Model
+
+	search      textinput.Model
+	searching   bool
+	query       string
+	matches     []int // line number of each match, in order of appearance
+	matchCursor int
 }
 
 type WithOpt func(*Model)
@@ -43,10 +52,14 @@ func WithHighPerformanceRenderer(enabled bool) func(m *Model) {
 }
 
 func New(opts ...WithOpt) Model {
+	search := textinput.New()
+	search.Prompt = "/"
+
 	m := Model{
 		cmdQuit:                    nil,
 		useHighPerformanceRenderer: false,
 		styles:                     DefaultStyles(),
+		search:                     search,
 	}
 
 	for _, opt := range opts {
@@ -72,20 +85,83 @@ func (m Model) View() string {
 	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.viewport.View(), m.footerView())
 }
 
+// IsCapturingInput reports whether the viewer is currently consuming key
+// presses (e.g. typing a search query), in which case parents should not
+// react to them.
+func (m Model) IsCapturingInput() bool { return m.searching }
+
 type ContentInput struct {
 	Title     string
 	SideTitle string
 	Content   string
+
+	// KeepPosition keeps the scroll position and current match, useful when
+	// refreshing the content
+	KeepPosition bool
 }
 
 func (m *Model) SetContent(msg ContentInput) {
 	m.title = msg.Title
 	m.sideTitle = msg.SideTitle
 	m.content = msg.Content
-	m.viewport.SetContent(msg.Content)
+	if msg.KeepPosition {
+		m.viewport.SetContent(m.highlight())
+		m.matchCursor = min(m.matchCursor, max(len(m.matches)-1, 0))
+		return
+	}
+
+	m.matchCursor = 0
+	m.viewport.SetContent(m.highlight())
 	m.viewport.GotoTop()
 }
 
+// highlight returns the content with all matches of the current query
+// highlighted, refreshing the list of matches along the way. Lines containing
+// a match lose their original styling, as styles can't be reliably split.
+func (m *Model) highlight() string {
+	m.matches = nil
+	if m.query == "" {
+		return m.content
+	}
+
+	lines := strings.Split(m.content, "\n")
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		haystack, needle := strings.ToLower(plain), strings.ToLower(m.query)
+		if len(haystack) != len(plain) {
+			// lowercasing changed byte offsets, fallback to case sensitive
+			haystack, needle = plain, m.query
+		}
+
+		if !strings.Contains(haystack, needle) {
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for {
+			idx := strings.Index(haystack[last:], needle)
+			if idx < 0 {
+				break
+			}
+			idx += last
+
+			style := m.styles.Match
+			if len(m.matches) == m.matchCursor {
+				style = m.styles.CurrentMatch
+			}
+			b.WriteString(plain[last:idx])
+			b.WriteString(style.Render(plain[idx : idx+len(needle)]))
+			m.matches = append(m.matches, i)
+			last = idx + len(needle)
+		}
+		b.WriteString(plain[last:])
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) headerView() string {
 	return lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -95,8 +171,19 @@ func (m Model) headerView() string {
 }
 
 func (m Model) footerView() string {
+	var search string
+	switch {
+	case m.searching:
+		search = m.search.View()
+	case m.query != "" && len(m.matches) == 0:
+		search = fmt.Sprintf("no matches for %q", m.query)
+	case m.query != "":
+		search = fmt.Sprintf("%d/%d matches for %q", m.matchCursor+1, len(m.matches), m.query)
+	}
+
 	return lipgloss.JoinHorizontal(
 		lipgloss.Right,
 		m.styles.Footer.Render(fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100)),
+		m.styles.Search.Render(search),
 	)
 }
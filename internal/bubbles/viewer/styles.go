@@ -10,6 +10,10 @@ type Styles struct {
 	SideTitle lipgloss.Style
 	Viewport  lipgloss.Style
 	Footer    lipgloss.Style
+
+	Search       lipgloss.Style
+	Match        lipgloss.Style
+	CurrentMatch lipgloss.Style
 }
 
 func DefaultStyles() Styles {
@@ -32,5 +36,13 @@ func DefaultStyles() Styles {
 			Padding(0, 1, 0, 1),
 		Footer: lipgloss.NewStyle().
 			Padding(0, 1, 0, 1),
+		Search: lipgloss.NewStyle().
+			Padding(0, 1, 0, 0),
+		Match: lipgloss.NewStyle().
+			Background(lipgloss.ANSIColor(ansi.Yellow)).
+			Foreground(lipgloss.ANSIColor(ansi.Black)),
+		CurrentMatch: lipgloss.NewStyle().
+			Background(lipgloss.ANSIColor(ansi.Magenta)).
+			Foreground(lipgloss.ANSIColor(ansi.White)),
 	}
 }
@@ -0,0 +1,333 @@
+// Package bundle writes and opens support bundles: gzipped tarballs with a
+// trace, the YAML of each of its objects and their events, so traces can be
+// explored offline by someone without access to the cluster.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/goccy/go-yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	manifestFile = "manifest.json"
+	traceFile    = "trace.json"
+	objectsDir   = "objects"
+	eventsDir    = "events"
+
+	// pathAnnotation keeps where an object is stored, if not at its default
+	// path (taken by another object redacted to the same name)
+	pathAnnotation = "crossplane-explorer.io/bundle-path"
+
+	// maxConcurrentEvents limits how many events queries run at once
+	maxConcurrentEvents = 8
+)
+
+// Manifest describes where and when a bundle was taken, and whether it was
+// redacted
+type Manifest struct {
+	Context   string    `json:"context,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Redacted  bool      `json:"redacted,omitempty"`
+}
+
+type options struct {
+	redactor *redact.Redactor
+}
+
+// Opt configures how bundles are written
+type Opt func(o *options)
+
+// WithRedactor redacts the trace, objects and events written into bundles
+func WithRedactor(r *redact.Redactor) Opt {
+	return func(o *options) {
+		o.redactor = r
+	}
+}
+
+type EventsGetter interface {
+	GetEvents(ctx context.Context, r *xplane.Resource) ([]corev1.Event, error)
+}
+
+// eventsFile keeps the events of an object, or why they couldn't be fetched
+type eventsFile struct {
+	Items []corev1.Event `json:"items"`
+	Error string         `json:"error,omitempty"`
+}
+
+// Write writes a bundle of a trace. Events are fetched for every object if a
+// getter is given, with failures recorded in the bundle.
+func Write(ctx context.Context, w io.Writer, trace *xplane.Resource, events EventsGetter, m Manifest, opts ...Opt) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	m.Redacted = o.redactor.Enabled()
+	m.Context = o.redactor.String(m.Context)
+	m.Cluster = o.redactor.String(m.Cluster)
+
+	// Events are fetched for the original resources, but stored along with
+	// the redacted ones (names might be redacted too)
+	resources := flatten(trace)
+	redacted := flatten(clone(o.redactor.Resource(trace)))
+	paths := objectPaths(redacted)
+
+	files := map[string][]byte{}
+	var err error
+	if files[manifestFile], err = json.MarshalIndent(m, "", "  "); err != nil {
+		return err
+	}
+	if files[traceFile], err = json.MarshalIndent(redacted[0], "", "  "); err != nil {
+		return fmt.Errorf("failed to encode trace: %w", err)
+	}
+	for i, r := range redacted {
+		if files[path.Join(objectsDir, paths[i]+".yaml")], err = yaml.Marshal(r.Unstructured.Object); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", paths[i], err)
+		}
+	}
+
+	if events != nil {
+		for name, data := range getEvents(ctx, resources, paths, events, o.redactor) {
+			files[name] = data
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), ModTime: m.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteFile writes a bundle into a new file
+func WriteFile(ctx context.Context, name string, trace *xplane.Resource, events EventsGetter, m Manifest, opts ...Opt) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := Write(ctx, f, trace, events, m, opts...); err != nil {
+		f.Close()
+		os.Remove(name)
+		return err
+	}
+	return f.Close()
+}
+
+// getEvents fetches the events of all resources concurrently, returning the
+// files they are stored in (at the paths of their redacted counterparts)
+func getEvents(
+	ctx context.Context,
+	resources []*xplane.Resource,
+	paths []string,
+	events EventsGetter,
+	redactor *redact.Redactor,
+) map[string][]byte {
+	files := map[string][]byte{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentEvents)
+
+	for i, r := range resources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var f eventsFile
+			var err error
+			if f.Items, err = events.GetEvents(ctx, r); err != nil {
+				f.Error = redactor.String(err.Error())
+			}
+			f.Items = redactor.Events(f.Items)
+			data, _ := json.MarshalIndent(f, "", "  ")
+
+			mu.Lock()
+			defer mu.Unlock()
+			files[path.Join(eventsDir, paths[i]+".json")] = data
+		}()
+	}
+	wg.Wait()
+
+	return files
+}
+
+// Bundle is an opened bundle, which serves its trace and events
+type Bundle struct {
+	Manifest Manifest
+	trace    []byte
+	events   map[string]eventsFile
+}
+
+// Open reads a bundle into memory
+func Open(name string) (*Bundle, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle %s: %w", name, err)
+	}
+	return b, nil
+}
+
+// Read reads a bundle from a stream
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	b := &Bundle{events: map[string]eventsFile{}}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		//nolint // bundles are meant to be opened by those who trust them
+		if _, err := io.Copy(&buf, tr); err != nil {
+			return nil, err
+		}
+
+		switch name := path.Clean(hdr.Name); {
+		case name == manifestFile:
+			if err := json.Unmarshal(buf.Bytes(), &b.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+		case name == traceFile:
+			b.trace = buf.Bytes()
+		case strings.HasPrefix(name, eventsDir+"/") && path.Ext(name) == ".json":
+			var f eventsFile
+			if err := json.Unmarshal(buf.Bytes(), &f); err != nil {
+				return nil, fmt.Errorf("invalid events %s: %w", name, err)
+			}
+			b.events[name] = f
+		}
+	}
+
+	if b.trace == nil {
+		return nil, fmt.Errorf("%s not found", traceFile)
+	}
+	return b, nil
+}
+
+// GetTrace returns a fresh copy of the bundled trace
+func (b *Bundle) GetTrace(_ context.Context) (*xplane.Resource, error) {
+	return xplane.Parse(bytes.NewReader(b.trace))
+}
+
+// GetEvents returns the bundled events of a resource
+func (b *Bundle) GetEvents(_ context.Context, r *xplane.Resource) ([]corev1.Event, error) {
+	f, ok := b.events[path.Join(eventsDir, objectPath(r)+".json")]
+	if !ok {
+		return nil, errors.New("events were not included in the bundle")
+	}
+	if f.Error != "" {
+		return nil, fmt.Errorf("events could not be bundled: %s", f.Error)
+	}
+	return f.Items, nil
+}
+
+// objectPaths returns where each object is stored in a bundle. Objects whose
+// path is taken already get a numbered suffix, kept in an annotation.
+func objectPaths(resources []*xplane.Resource) []string {
+	paths := make([]string, len(resources))
+	taken := map[string]bool{}
+	for i, r := range resources {
+		p := objectPath(r)
+		for n := 2; taken[p]; n++ {
+			p = fmt.Sprintf("%s-%d", objectPath(r), n)
+		}
+		if p != objectPath(r) {
+			annotations := r.Unstructured.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[pathAnnotation] = p
+			r.Unstructured.SetAnnotations(annotations)
+		}
+		taken[p] = true
+		paths[i] = p
+	}
+	return paths
+}
+
+// objectPath returns where an object is stored in a bundle, as
+// kind.group/namespace/name (with _cluster as namespace if cluster scoped)
+// unless annotated otherwise
+func objectPath(r *xplane.Resource) string {
+	if p := r.Unstructured.GetAnnotations()[pathAnnotation]; p != "" {
+		return p
+	}
+
+	ns := r.Unstructured.GetNamespace()
+	if ns == "" {
+		ns = "_cluster"
+	}
+	gk := r.Unstructured.GroupVersionKind().GroupKind()
+	return path.Join(gk.String(), ns, r.Unstructured.GetName())
+}
+
+// clone deep copies a trace, so it can be annotated
+func clone(r *xplane.Resource) *xplane.Resource {
+	c := &xplane.Resource{Error: r.Error, Children: make([]*xplane.Resource, len(r.Children))}
+	c.Unstructured.Object = runtime.DeepCopyJSON(r.Unstructured.Object)
+	for i, child := range r.Children {
+		c.Children[i] = clone(child)
+	}
+	return c
+}
+
+func flatten(r *xplane.Resource) []*xplane.Resource {
+	resources := []*xplane.Resource{r}
+	for _, c := range r.Children {
+		resources = append(resources, flatten(c)...)
+	}
+	return resources
+}
@@ -0,0 +1,172 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type fakeEvents map[string]error
+
+func (f fakeEvents) GetEvents(_ context.Context, r *xplane.Resource) ([]corev1.Event, error) {
+	if err := f[r.Unstructured.GetName()]; err != nil {
+		return nil, err
+	}
+	return []corev1.Event{{Reason: "Synced", Message: r.Unstructured.GetName()}}, nil
+}
+
+func newResource(kind, name string, children ...*xplane.Resource) *xplane.Resource {
+	return &xplane.Resource{
+		Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name},
+		}},
+		Children: children,
+	}
+}
+
+func TestBundle(t *testing.T) {
+	trace := newResource("XBucket", "root",
+		newResource("Bucket", "bucket"),
+		newResource("Policy", "policy"),
+	)
+	manifest := Manifest{Context: "staging", CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	events := fakeEvents{"policy": errors.New("forbidden")}
+
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, trace, events, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Manifest != manifest {
+		t.Errorf("Should keep the manifest\nManifest = %+v, want %+v", b.Manifest, manifest)
+	}
+
+	got, err := b.GetTrace(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Children) != 2 || got.Children[1].Unstructured.GetName() != "policy" {
+		t.Fatalf("Should keep the trace\nGetTrace() = %+v", got)
+	}
+
+	type want struct {
+		message string
+		err     string
+	}
+	tests := map[string]struct {
+		reason   string
+		resource *xplane.Resource
+		want     want
+	}{
+		"Events": {
+			reason:   "Should return the bundled events",
+			resource: got.Children[0],
+			want:     want{message: "bucket"},
+		},
+		"EventsFailed": {
+			reason:   "Should return why events could not be bundled",
+			resource: got.Children[1],
+			want:     want{err: "events could not be bundled: forbidden"},
+		},
+		"Unknown": {
+			reason:   "Should fail for resources not in the bundle",
+			resource: newResource("Bucket", "unknown"),
+			want:     want{err: "events were not included in the bundle"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			events, err := b.GetEvents(context.Background(), tc.resource)
+			if tc.want.err != "" {
+				if err == nil || err.Error() != tc.want.err {
+					t.Errorf("%s\nGetEvents() error = %v, want %q", tc.reason, err, tc.want.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s\nGetEvents() error = %v", tc.reason, err)
+			}
+			if len(events) != 1 || events[0].Message != tc.want.message {
+				t.Errorf("%s\nGetEvents() = %+v, want message %q", tc.reason, events, tc.want.message)
+			}
+		})
+	}
+}
+
+func TestBundleRedaction(t *testing.T) {
+	redactor, err := redact.New(redact.Config{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both names get redacted into the same one
+	trace := newResource("XNetwork", "root",
+		newResource("Address", "10.0.0.1"),
+		newResource("Address", "10.0.0.2"),
+	)
+	arn := "arn:aws:eks:eu-west-1:123456789012:cluster/prod"
+	manifest := Manifest{Context: arn, Cluster: arn, CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	events := fakeEvents{"10.0.0.2": errors.New("forbidden")}
+
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, trace, events, manifest, WithRedactor(redactor)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantManifest := Manifest{Context: redact.DefaultReplacement, Cluster: redact.DefaultReplacement, CreatedAt: manifest.CreatedAt, Redacted: true}
+	if b.Manifest != wantManifest {
+		t.Errorf("Should redact the manifest\nManifest = %+v, want %+v", b.Manifest, wantManifest)
+	}
+
+	got, err := b.GetTrace(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		err string
+	}
+	tests := map[string]struct {
+		reason   string
+		resource *xplane.Resource
+		want     want
+	}{
+		"First": {
+			reason:   "Should keep the events of the first object redacted into a name",
+			resource: got.Children[0],
+		},
+		"Colliding": {
+			reason:   "Should keep the events of objects redacted into a taken name apart",
+			resource: got.Children[1],
+			want:     want{err: "events could not be bundled: forbidden"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := b.GetEvents(context.Background(), tc.resource)
+			if tc.want.err == "" && err != nil {
+				t.Errorf("%s\nGetEvents() error = %v", tc.reason, err)
+			}
+			if tc.want.err != "" && (err == nil || err.Error() != tc.want.err) {
+				t.Errorf("%s\nGetEvents() error = %v, want %q", tc.reason, err, tc.want.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Write copies text into the system clipboard. If there is no system
+// clipboard available (e.g. over SSH or within containers), it falls back to
+// the OSC52 terminal escape sequence, which most modern terminals support. It
+// is written into term, which should be shared with the program rendering
+// into the terminal.
+func Write(term io.Writer, text string) error {
+	errSystem := clipboard.WriteAll(text)
+	if errSystem == nil && !isRemote() {
+		return nil
+	}
+
+	errOSC52 := writeOSC52(term, text)
+	if errOSC52 == nil {
+		return nil
+	}
+
+	if errSystem == nil {
+		return nil
+	}
+	return errors.Join(
+		fmt.Errorf("system clipboard: %w", errSystem),
+		fmt.Errorf("osc52: %w", errOSC52),
+	)
+}
+
+// isRemote reports whether the program seems to be running through SSH, in
+// which case the system clipboard is not the one the user can paste from
+func isRemote() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+func writeOSC52(w io.Writer, text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	_, err := seq.WriteTo(w)
+	return err
+}
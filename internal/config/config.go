@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/action"
+	"github.com/brunoluiz/crossplane-explorer/internal/notify"
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
+	"github.com/goccy/go-yaml"
+)
+
+// Config holds user preferences, usually loaded from DefaultPath
+type Config struct {
+	Actions       []action.Action `yaml:"actions"`
+	Notifications notify.Config   `yaml:"notifications"`
+	Redaction     redact.Config   `yaml:"redaction"`
+}
+
+// DefaultPath returns the default config location, which follows the OS
+// conventions (e.g. `~/.config/crossplane-explorer/config.yaml` on Linux)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "crossplane-explorer", "config.yaml")
+}
+
+// Load reads the config from a file. A missing file is not an error, as all
+// settings are optional.
+func Load(path string) (Config, error) {
+	cfg := Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) Validate() error {
+	keys := map[string]string{}
+	for i := range c.Actions {
+		a := &c.Actions[i]
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if other, ok := keys[a.Key]; ok {
+			return fmt.Errorf("actions %q and %q are bound to the same key %q", other, a.Name, a.Key)
+		}
+		keys[a.Key] = a.Name
+	}
+	return nil
+}
+
+// CheckReservedKeys fails if any action is bound to a reserved key
+func (c *Config) CheckReservedKeys(isReserved func(key string) bool) error {
+	for _, a := range c.Actions {
+		if isReserved(a.Key) {
+			return fmt.Errorf("action %q: key %q is reserved", a.Name, a.Key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	type args struct {
+		content string
+		missing bool
+	}
+
+	type want struct {
+		actions int
+		err     bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Missing": {
+			reason: "Should return an empty config if the file does not exist",
+			args:   args{missing: true},
+			want:   want{},
+		},
+		"Actions": {
+			reason: "Should load and validate actions",
+			args: args{content: `
+actions:
+  - name: logs
+    key: L
+    command: kubectl logs {{ .Name }}
+  - name: console
+    key: O
+    mode: exec
+    command: open {{ .Name }}
+`},
+			want: want{actions: 2},
+		},
+		"InvalidYAML": {
+			reason: "Should fail for files which are not valid YAML",
+			args:   args{content: "actions: [\n"},
+			want:   want{err: true},
+		},
+		"InvalidAction": {
+			reason: "Should fail for invalid actions",
+			args:   args{content: "actions:\n  - name: logs\n    command: echo\n"},
+			want:   want{err: true},
+		},
+		"DuplicateKeys": {
+			reason: "Should fail for actions bound to the same key",
+			args: args{content: `
+actions:
+  - name: logs
+    key: L
+    command: echo logs
+  - name: links
+    key: L
+    command: echo links
+`},
+			want: want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if !tc.args.missing {
+				if err := os.WriteFile(path, []byte(tc.args.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load(path)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("%s\nLoad() error = %v, want error %v", tc.reason, err, tc.want.err)
+			}
+			if err == nil && len(cfg.Actions) != tc.want.actions {
+				t.Errorf("%s\nLoad() actions = %d, want %d", tc.reason, len(cfg.Actions), tc.want.actions)
+			}
+		})
+	}
+}
+
+func TestCheckReservedKeys(t *testing.T) {
+	reserved := func(key string) bool { return key == "q" }
+
+	type args struct {
+		content string
+	}
+
+	type want struct {
+		err bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Free": {
+			reason: "Should accept actions bound to free keys",
+			args:   args{content: "actions:\n  - name: logs\n    key: L\n    command: echo\n"},
+			want:   want{},
+		},
+		"Reserved": {
+			reason: "Should fail for actions bound to reserved keys",
+			args:   args{content: "actions:\n  - name: logs\n    key: q\n    command: echo\n"},
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tc.args.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cfg.CheckReservedKeys(reserved); (err != nil) != tc.want.err {
+				t.Errorf("%s\nCheckReservedKeys() error = %v, want error %v", tc.reason, err, tc.want.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package kube
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Context selects which cluster to talk to. Empty fields follow the kubectl
+// conventions (KUBECONFIG, ~/.kube/config and its current context).
+type Context struct {
+	Kubeconfig string
+	Name       string
+}
+
+func (c Context) clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = c.Kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: c.Name})
+}
+
+func (c Context) RESTConfig() (*rest.Config, error) {
+	return c.clientConfig().ClientConfig()
+}
+
+// Current returns the names of the context and cluster in use
+func (c Context) Current() (string, string, error) {
+	raw, err := c.clientConfig().RawConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	name := c.Name
+	if name == "" {
+		name = raw.CurrentContext
+	}
+	kctx, ok := raw.Contexts[name]
+	if !ok {
+		return "", "", fmt.Errorf("context %q not found in kubeconfig", name)
+	}
+	return name, kctx.Cluster, nil
+}
+
+// Env returns the environment variables which make commands, such as kubectl
+// and crossplane, talk to the selected cluster. When a context is selected, a
+// copy of the kubeconfig using it is written to a temporary file, which is
+// removed by cleanup.
+func (c Context) Env() ([]string, func(), error) {
+	noop := func() {}
+	if c.Name == "" {
+		if c.Kubeconfig == "" {
+			return nil, noop, nil
+		}
+		path, err := filepath.Abs(c.Kubeconfig)
+		if err != nil {
+			return nil, noop, err
+		}
+		return []string{"KUBECONFIG=" + path}, noop, nil
+	}
+
+	raw, err := c.clientConfig().RawConfig()
+	if err != nil {
+		return nil, noop, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if _, ok := raw.Contexts[c.Name]; !ok {
+		return nil, noop, fmt.Errorf("context %q not found in kubeconfig", c.Name)
+	}
+	raw.CurrentContext = c.Name
+
+	// CreateTemp restricts permissions to the current user, as it holds credentials
+	f, err := os.CreateTemp("", "crossplane-explorer-*.kubeconfig")
+	if err != nil {
+		return nil, noop, err
+	}
+	_ = f.Close()
+	cleanup := func() { _ = os.Remove(f.Name()) }
+
+	if err := clientcmd.WriteToFile(raw, f.Name()); err != nil {
+		cleanup()
+		return nil, noop, fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return []string{"KUBECONFIG=" + f.Name()}, cleanup, nil
+}
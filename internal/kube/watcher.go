@@ -0,0 +1,210 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// refPaths are where composites and claims reference the resources they own
+var refPaths = [][]string{
+	{"spec", "resourceRefs"},
+	{"spec", "resourceRef"},
+	{"spec", "crossplane", "resourceRefs"},
+}
+
+// Watcher watches the objects of a trace through the Kubernetes API
+type Watcher struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+func NewWatcher(client dynamic.Interface, mapper meta.RESTMapper) *Watcher {
+	return &Watcher{client: client, mapper: mapper}
+}
+
+func NewWatcherForConfig(cfg *rest.Config) (*Watcher, error) {
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return NewWatcher(client, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))), nil
+}
+
+type target struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// Watch establishes watches on the kinds present in the trace and streams
+// updated versions of its resources. Changes which alter the tree structure,
+// such as deletions or new resource references, end the stream (as does any
+// watch failure), signalling the trace must be fetched again.
+func (w *Watcher) Watch(ctx context.Context, trace *xplane.Resource) (<-chan *xplane.Resource, error) {
+	known := map[string]*unstructured.Unstructured{}
+	targets := map[target]bool{}
+
+	var visit func(r *xplane.Resource) error
+	visit = func(r *xplane.Resource) error {
+		gvk := r.Unstructured.GroupVersionKind()
+		if r.Unstructured.GetName() == "" || gvk.Kind == "" {
+			return nil // e.g. resources which failed to be fetched
+		}
+
+		mapping, err := w.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("failed to map %s: %w", gvk, err)
+		}
+
+		t := target{gvr: mapping.Resource}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			t.namespace = r.Unstructured.GetNamespace()
+		}
+		targets[t] = true
+		known[r.Key()] = &r.Unstructured
+
+		for _, c := range r.Children {
+			if err := visit(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(trace); err != nil {
+		return nil, err
+	}
+
+	watchers := make([]watch.Interface, 0, len(targets))
+	stop := func() {
+		for _, wi := range watchers {
+			wi.Stop()
+		}
+	}
+	for t := range targets {
+		wi, err := w.client.Resource(t.gvr).Namespace(t.namespace).Watch(ctx, metav1.ListOptions{})
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("failed to watch %s: %w", t.gvr.GroupResource(), err)
+		}
+		watchers = append(watchers, wi)
+	}
+
+	out := make(chan *xplane.Resource)
+	go func() {
+		defer close(out)
+		defer stop()
+
+		done := make(chan struct{})
+		defer close(done)
+
+		events := make(chan watch.Event)
+		for _, wi := range watchers {
+			go forward(wi, events, done)
+		}
+
+		for {
+			var e watch.Event
+			select {
+			case <-ctx.Done():
+				return
+			case e = <-events:
+			}
+
+			r, cont := onEvent(e, known)
+			if !cont {
+				return
+			}
+			if r == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- r:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// forward sends all events from a watch to a single channel, followed by an
+// error once the watch is closed
+func forward(wi watch.Interface, events chan<- watch.Event, done <-chan struct{}) {
+	for e := range wi.ResultChan() {
+		select {
+		case events <- e:
+		case <-done:
+			return
+		}
+	}
+
+	select {
+	case events <- watch.Event{Type: watch.Error}:
+	case <-done:
+	}
+}
+
+// onEvent returns the resource to be published, if any, and whether the
+// stream should continue
+func onEvent(e watch.Event, known map[string]*unstructured.Unstructured) (*xplane.Resource, bool) {
+	switch e.Type {
+	case watch.Added, watch.Modified, watch.Deleted:
+	case watch.Bookmark:
+		return nil, true
+	default:
+		return nil, false
+	}
+
+	u, ok := e.Object.(*unstructured.Unstructured)
+	if !ok {
+		return nil, true
+	}
+
+	r := &xplane.Resource{Unstructured: *u}
+	prev, ok := known[r.Key()]
+	switch {
+	case !ok:
+		// Other objects of the same kind, or new ones which are only part of
+		// the tree once referenced by their owner
+		return nil, true
+	case e.Type == watch.Deleted:
+		return nil, false
+	case prev.GetResourceVersion() == u.GetResourceVersion():
+		// Watches start by replaying the current state as added objects
+		return nil, true
+	case !reflect.DeepEqual(refs(prev), refs(u)):
+		return nil, false
+	}
+
+	known[r.Key()] = u
+	return r, true
+}
+
+func refs(u *unstructured.Unstructured) []any {
+	out := make([]any, 0, len(refPaths))
+	for _, p := range refPaths {
+		v, _, _ := unstructured.NestedFieldNoCopy(u.Object, p...)
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	xbucketGVK = schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XBucket"}
+	bucketGVK  = schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+)
+
+func newObject(gvk schema.GroupVersionKind, name, rv string, refs ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(name)
+	u.SetResourceVersion(rv)
+	if len(refs) > 0 {
+		items := []any{}
+		for _, r := range refs {
+			items = append(items, map[string]any{"name": r})
+		}
+		_ = unstructured.SetNestedSlice(u.Object, items, "spec", "resourceRefs")
+	}
+	return u
+}
+
+func newTrace() *xplane.Resource {
+	return &xplane.Resource{
+		Unstructured: *newObject(xbucketGVK, "root", "1", "child"),
+		Children: []*xplane.Resource{
+			{Unstructured: *newObject(bucketGVK, "child", "1")},
+		},
+	}
+}
+
+func newTestWatcher(t *testing.T, reactor k8stesting.WatchReactionFunc) *Watcher {
+	t.Helper()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(xbucketGVK, meta.RESTScopeRoot)
+	mapper.Add(bucketGVK, meta.RESTScopeRoot)
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependWatchReactor("*", reactor)
+	return NewWatcher(client, mapper)
+}
+
+func TestWatcher(t *testing.T) {
+	type args struct {
+		events func(w *watch.FakeWatcher)
+	}
+
+	type want struct {
+		updates []string
+		closed  bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Modified": {
+			reason: "Should publish updated resources which are part of the trace",
+			args: args{
+				events: func(w *watch.FakeWatcher) {
+					w.Modify(newObject(bucketGVK, "child", "2"))
+				},
+			},
+			want: want{updates: []string{"child@2"}},
+		},
+		"Replayed": {
+			reason: "Should skip objects which did not change since the trace",
+			args: args{
+				events: func(w *watch.FakeWatcher) {
+					w.Add(newObject(bucketGVK, "child", "1"))
+					w.Modify(newObject(bucketGVK, "child", "2"))
+				},
+			},
+			want: want{updates: []string{"child@2"}},
+		},
+		"Unknown": {
+			reason: "Should skip objects which are not part of the trace",
+			args: args{
+				events: func(w *watch.FakeWatcher) {
+					w.Modify(newObject(bucketGVK, "other", "2"))
+					w.Modify(newObject(bucketGVK, "child", "3"))
+				},
+			},
+			want: want{updates: []string{"child@3"}},
+		},
+		"NewRefs": {
+			reason: "Should end the stream once the tree structure changes",
+			args: args{
+				events: func(w *watch.FakeWatcher) {
+					w.Modify(newObject(xbucketGVK, "root", "2", "child", "another"))
+				},
+			},
+			want: want{updates: []string{}, closed: true},
+		},
+		"Deleted": {
+			reason: "Should end the stream once a resource is deleted",
+			args: args{
+				events: func(w *watch.FakeWatcher) {
+					w.Delete(newObject(bucketGVK, "child", "2"))
+				},
+			},
+			want: want{updates: []string{}, closed: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fw := watch.NewFakeWithChanSize(10, false)
+			w := newTestWatcher(t, k8stesting.DefaultWatchReactor(fw, nil))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch, err := w.Watch(ctx, newTrace())
+			if err != nil {
+				t.Fatalf("%s\nWatch() error = %v", tc.reason, err)
+			}
+			tc.args.events(fw)
+
+			got := []string{}
+			closed := false
+			for len(got) < len(tc.want.updates) && !closed {
+				select {
+				case r, ok := <-ch:
+					if !ok {
+						closed = true
+						break
+					}
+					got = append(got, r.Unstructured.GetName()+"@"+r.Unstructured.GetResourceVersion())
+				case <-time.After(time.Second):
+					t.Fatalf("%s\nWatch() timed out, got %v", tc.reason, got)
+				}
+			}
+			if tc.want.closed && !closed {
+				select {
+				case _, ok := <-ch:
+					closed = !ok
+				case <-time.After(time.Second):
+				}
+			}
+
+			if closed != tc.want.closed {
+				t.Errorf("%s\nWatch() closed = %v, want %v", tc.reason, closed, tc.want.closed)
+			}
+			if len(got) != len(tc.want.updates) {
+				t.Fatalf("%s\nWatch() = %v, want %v", tc.reason, got, tc.want.updates)
+			}
+			for i := range got {
+				if got[i] != tc.want.updates[i] {
+					t.Errorf("%s\nWatch() = %v, want %v", tc.reason, got, tc.want.updates)
+				}
+			}
+		})
+	}
+}
+
+func TestWatcherForbidden(t *testing.T) {
+	w := newTestWatcher(t, func(_ k8stesting.Action) (bool, watch.Interface, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "buckets"}, "", nil)
+	})
+
+	_, err := w.Watch(context.Background(), newTrace())
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("Should return a forbidden error if watches are not permitted\nWatch() error = %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"text/template"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/action"
+)
+
+type Scope string
+
+const (
+	ScopeAny  Scope = "any"
+	ScopeRoot Scope = "root"
+)
+
+// Rule selects which transitions trigger notifications, such as the root
+// becoming Ready=True or any resource becoming Synced=False
+type Rule struct {
+	Resource  Scope  `yaml:"resource"`
+	Condition string `yaml:"condition"`
+	Status    string `yaml:"status"`
+}
+
+func (r Rule) Matches(t Transition) bool {
+	if r.Resource == ScopeRoot && !t.Root {
+		return false
+	}
+	return r.Condition == t.Condition && r.Status == t.To
+}
+
+// Config defines notification channels and rules. Commands are Go templates,
+// with the same helpers as actions (see Data for the available fields).
+type Config struct {
+	Bell           bool   `yaml:"bell"`
+	Desktop        bool   `yaml:"desktop"`
+	DesktopCommand string `yaml:"desktopCommand"`
+	Command        string `yaml:"command"`
+	Rules          []Rule `yaml:"rules"`
+}
+
+// Data is available within notification command templates as `.`
+type Data struct {
+	Message   string
+	Kind      string
+	Name      string
+	Namespace string
+	Condition string
+	From      string
+	To        string
+}
+
+func defaultDesktopCommand() string {
+	if runtime.GOOS == "darwin" {
+		return `osascript -e {{ printf "display notification %q with title \"crossplane-explorer\"" .Message }}`
+	}
+	return `notify-send crossplane-explorer {{ .Message }}`
+}
+
+// Notifier sends notifications for transitions matching its rules
+type Notifier struct {
+	cfg     Config
+	out     io.Writer
+	desktop *template.Template
+	command *template.Template
+}
+
+// Opt configures a Notifier
+type Opt func(n *Notifier)
+
+// WithOutput sets where the bell is rung, which should be shared with the
+// program rendering into the terminal (os.Stdout by default)
+func WithOutput(w io.Writer) Opt {
+	return func(n *Notifier) { n.out = w }
+}
+
+func New(cfg Config, opts ...Opt) (*Notifier, error) {
+	n := &Notifier{cfg: cfg, out: os.Stdout}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	for _, r := range cfg.Rules {
+		if r.Resource != "" && r.Resource != ScopeAny && r.Resource != ScopeRoot {
+			return nil, fmt.Errorf("notification rule: unknown resource %q", r.Resource)
+		}
+		if r.Condition == "" || r.Status == "" {
+			return nil, errors.New("notification rule: condition and status are required")
+		}
+	}
+
+	var err error
+	if cfg.Desktop {
+		cmd := cfg.DesktopCommand
+		if cmd == "" {
+			cmd = defaultDesktopCommand()
+		}
+		if n.desktop, err = action.Parse("desktop", cmd); err != nil {
+			return nil, fmt.Errorf("notification desktop command: %w", err)
+		}
+	}
+	if cfg.Command != "" {
+		if n.command, err = action.Parse("command", cfg.Command); err != nil {
+			return nil, fmt.Errorf("notification command: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+// Enabled reports whether there is any rule and channel configured
+func (n *Notifier) Enabled() bool {
+	return len(n.cfg.Rules) > 0 && (n.cfg.Bell || n.desktop != nil || n.command != nil)
+}
+
+// Matching returns the transitions matching at least one rule
+func (n *Notifier) Matching(transitions []Transition) []Transition {
+	out := []Transition{}
+	for _, t := range transitions {
+		for _, r := range n.cfg.Rules {
+			if r.Matches(t) {
+				out = append(out, t)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Notify sends a notification through all configured channels
+func (n *Notifier) Notify(t Transition) error {
+	var errs []error
+	if n.cfg.Bell {
+		if _, err := io.WriteString(n.out, "\a"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	data := Data{
+		Message:   t.String(),
+		Kind:      t.Resource.Unstructured.GetKind(),
+		Name:      t.Resource.Unstructured.GetName(),
+		Namespace: t.Resource.Unstructured.GetNamespace(),
+		Condition: t.Condition,
+		From:      t.From,
+		To:        t.To,
+	}
+	for _, tmpl := range []*template.Template{n.desktop, n.command} {
+		if tmpl == nil {
+			continue
+		}
+		if err := run(tmpl, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func run(tmpl *template.Template, data Data) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Errorf("%s: %w", tmpl.Name(), err)
+	}
+
+	//nolint // commands are defined by the user
+	if out, err := exec.Command("sh", "-c", b.String()).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", tmpl.Name(), err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Transition is a condition status change of a resource between two traces
+type Transition struct {
+	Resource  *xplane.Resource
+	Root      bool
+	Condition string
+	From      string
+	To        string
+}
+
+func (t Transition) String() string {
+	return fmt.Sprintf(
+		"%s/%s became %s=%s",
+		t.Resource.Unstructured.GetKind(),
+		t.Resource.Unstructured.GetName(),
+		t.Condition,
+		t.To,
+	)
+}
+
+// Diff returns all condition transitions from prev to curr. Resources which
+// are new in curr transition from an empty status.
+func Diff(prev, curr *xplane.Resource) []Transition {
+	before := map[string]map[xpv1.ConditionType]string{}
+	walk(prev, func(r *xplane.Resource, _ bool) {
+		before[r.Key()] = conditions(r)
+	})
+
+	transitions := []Transition{}
+	walk(curr, func(r *xplane.Resource, root bool) {
+		old := before[r.Key()]
+		for _, c := range r.GetConditions() {
+			if old[c.Type] == string(c.Status) {
+				continue
+			}
+			transitions = append(transitions, Transition{
+				Resource:  r,
+				Root:      root,
+				Condition: string(c.Type),
+				From:      old[c.Type],
+				To:        string(c.Status),
+			})
+		}
+	})
+
+	return transitions
+}
+
+func conditions(r *xplane.Resource) map[xpv1.ConditionType]string {
+	out := map[xpv1.ConditionType]string{}
+	for _, c := range r.GetConditions() {
+		out[c.Type] = string(c.Status)
+	}
+	return out
+}
+
+func walk(r *xplane.Resource, fn func(r *xplane.Resource, root bool)) {
+	var visit func(r *xplane.Resource, root bool)
+	visit = func(r *xplane.Resource, root bool) {
+		if r == nil {
+			return
+		}
+		fn(r, root)
+		for _, c := range r.Children {
+			visit(c, false)
+		}
+	}
+	visit(r, true)
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResource(kind, name, synced, ready string, children ...*xplane.Resource) *xplane.Resource {
+	return &xplane.Resource{
+		Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name},
+			"status": map[string]any{
+				"conditions": []any{
+					map[string]any{"type": "Synced", "status": synced},
+					map[string]any{"type": "Ready", "status": ready},
+				},
+			},
+		}},
+		Children: children,
+	}
+}
+
+func TestNotifierMatching(t *testing.T) {
+	n, err := New(Config{
+		Bell: true,
+		Rules: []Rule{
+			{Resource: ScopeRoot, Condition: "Ready", Status: "True"},
+			{Resource: ScopeAny, Condition: "Synced", Status: "False"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		prev *xplane.Resource
+		curr *xplane.Resource
+	}
+
+	type want struct {
+		messages []string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "Should not notify if no status changed",
+			args: args{
+				prev: newResource("XBucket", "root", "True", "False"),
+				curr: newResource("XBucket", "root", "True", "False"),
+			},
+			want: want{messages: []string{}},
+		},
+		"RootReady": {
+			reason: "Should notify when the root becomes ready",
+			args: args{
+				prev: newResource("XBucket", "root", "True", "False"),
+				curr: newResource("XBucket", "root", "True", "True"),
+			},
+			want: want{messages: []string{"XBucket/root became Ready=True"}},
+		},
+		"ChildReady": {
+			reason: "Should not notify when a child becomes ready, as the rule is scoped to the root",
+			args: args{
+				prev: newResource("XBucket", "root", "True", "False", newResource("Bucket", "child", "True", "False")),
+				curr: newResource("XBucket", "root", "True", "False", newResource("Bucket", "child", "True", "True")),
+			},
+			want: want{messages: []string{}},
+		},
+		"NewChildNotSynced": {
+			reason: "Should notify when a new resource shows up not synced",
+			args: args{
+				prev: newResource("XBucket", "root", "True", "False"),
+				curr: newResource("XBucket", "root", "True", "False", newResource("Bucket", "child", "False", "False")),
+			},
+			want: want{messages: []string{"Bucket/child became Synced=False"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := []string{}
+			for _, tr := range n.Matching(Diff(tc.args.prev, tc.args.curr)) {
+				got = append(got, tr.String())
+			}
+			if len(got) != len(tc.want.messages) {
+				t.Fatalf("%s\nMatching() = %v, want %v", tc.reason, got, tc.want.messages)
+			}
+			for i := range got {
+				if got[i] != tc.want.messages[i] {
+					t.Errorf("%s\nMatching() = %v, want %v", tc.reason, got, tc.want.messages)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Package redact hides sensitive data, such as account IDs, ARNs and IP
+// addresses, from whatever leaves the explorer: yanks, bundles and reports.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DefaultReplacement replaces redacted values, unless configured otherwise
+const DefaultReplacement = "REDACTED"
+
+// BuiltinFields hold references to connection secrets
+var BuiltinFields = []string{
+	"spec.writeConnectionSecretToRef",
+	"spec.publishConnectionDetailsTo",
+}
+
+// BuiltinPatterns match ARNs, AWS account IDs and IPv4 addresses (and CIDRs)
+var BuiltinPatterns = []string{
+	`\barn:aws[\w-]*:[^\s"']+`,
+	`\b\d{12}\b`,
+	`\b(?:\d{1,3}\.){3}\d{1,3}(?:/\d{1,2})?\b`,
+}
+
+// Config holds the redaction rules. Built-in rules are used unless disabled,
+// on top of the configured ones.
+type Config struct {
+	Enabled        bool     `yaml:"enabled"`
+	DisableBuiltin bool     `yaml:"disableBuiltin"`
+	Fields         []string `yaml:"fields"`
+	Patterns       []string `yaml:"patterns"`
+	Replacement    string   `yaml:"replacement"`
+}
+
+// Redactor replaces the values of fields and anything matching patterns. A
+// nil Redactor leaves everything untouched, so it can be used unconditionally.
+type Redactor struct {
+	fields      []string
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// New returns a redactor for the given rules, or nil if redaction is disabled
+func New(cfg Config) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	r := &Redactor{replacement: cfg.Replacement}
+	if r.replacement == "" {
+		r.replacement = DefaultReplacement
+	}
+
+	fields, patterns := cfg.Fields, cfg.Patterns
+	if !cfg.DisableBuiltin {
+		fields = slices.Concat(BuiltinFields, fields)
+		patterns = slices.Concat(BuiltinPatterns, patterns)
+	}
+
+	for _, f := range fields {
+		if _, err := fieldpath.Parse(f); err != nil {
+			return nil, fmt.Errorf("invalid redaction field %q: %w", f, err)
+		}
+	}
+	r.fields = fields
+
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// Enabled reports whether anything gets redacted
+func (r *Redactor) Enabled() bool { return r != nil }
+
+// String redacts anything matching the patterns
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
+
+// Object returns a redacted copy of an object. All strings within the fields
+// are replaced, while the structure of the object is kept.
+func (r *Redactor) Object(obj map[string]any) map[string]any {
+	if r == nil {
+		return obj
+	}
+
+	p := fieldpath.Pave(runtime.DeepCopyJSON(obj))
+	for _, path := range r.fields {
+		expanded, err := p.ExpandWildcards(path)
+		if err != nil {
+			continue
+		}
+		for _, e := range expanded {
+			v, err := p.GetValue(e)
+			if err != nil {
+				continue
+			}
+			//nolint // the field was just found
+			p.SetValue(e, r.replaceAll(v))
+		}
+	}
+
+	return r.redactStrings(p.UnstructuredContent()).(map[string]any)
+}
+
+// Resource returns a redacted copy of a trace
+func (r *Redactor) Resource(res *xplane.Resource) *xplane.Resource {
+	if r == nil || res == nil {
+		return res
+	}
+
+	c := &xplane.Resource{Children: make([]*xplane.Resource, len(res.Children))}
+	c.Unstructured.Object = r.Object(res.Unstructured.Object)
+	if res.Error != nil {
+		e := *res.Error
+		e.ErrStatus.Message = r.String(e.ErrStatus.Message)
+		c.Error = &e
+	}
+	for i, child := range res.Children {
+		c.Children[i] = r.Resource(child)
+	}
+	return c
+}
+
+// Events returns redacted copies of events
+func (r *Redactor) Events(events []corev1.Event) []corev1.Event {
+	if r == nil {
+		return events
+	}
+
+	redacted := make([]corev1.Event, 0, len(events))
+	for _, e := range events {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&e)
+		if err != nil {
+			// Better to leave an event out than to leak it
+			continue
+		}
+		var re corev1.Event
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(r.Object(obj), &re); err != nil {
+			continue
+		}
+		redacted = append(redacted, re)
+	}
+	return redacted
+}
+
+// replaceAll replaces all strings within a value
+func (r *Redactor) replaceAll(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, vv := range v {
+			v[k] = r.replaceAll(vv)
+		}
+		return v
+	case []any:
+		for i, vv := range v {
+			v[i] = r.replaceAll(vv)
+		}
+		return v
+	case string:
+		return r.replacement
+	default:
+		return v
+	}
+}
+
+// redactStrings redacts patterns in all strings within a value
+func (r *Redactor) redactStrings(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, vv := range v {
+			v[k] = r.redactStrings(vv)
+		}
+		return v
+	case []any:
+		for i, vv := range v {
+			v[i] = r.redactStrings(vv)
+		}
+		return v
+	case string:
+		return r.String(v)
+	default:
+		return v
+	}
+}
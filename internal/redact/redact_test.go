@@ -0,0 +1,105 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	type args struct {
+		cfg Config
+		obj map[string]any
+	}
+
+	type want struct {
+		obj map[string]any
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "Should leave objects untouched when disabled",
+			args: args{
+				obj: map[string]any{"status": map[string]any{"arn": "arn:aws:s3:::bucket"}},
+			},
+			want: want{
+				obj: map[string]any{"status": map[string]any{"arn": "arn:aws:s3:::bucket"}},
+			},
+		},
+		"Builtin": {
+			reason: "Should redact ARNs, account IDs, IP addresses and connection secret references",
+			args: args{
+				cfg: Config{Enabled: true},
+				obj: map[string]any{
+					"spec": map[string]any{
+						"writeConnectionSecretToRef": map[string]any{"name": "secret", "namespace": "default"},
+						"cidrBlocks":                 []any{"10.0.0.0/16"},
+					},
+					"status": map[string]any{
+						"arn":     "arn:aws:iam::123456789012:role/admin",
+						"message": "account 123456789012 is not allowed from 192.168.1.1",
+						"count":   int64(3),
+					},
+				},
+			},
+			want: want{
+				obj: map[string]any{
+					"spec": map[string]any{
+						"writeConnectionSecretToRef": map[string]any{"name": "REDACTED", "namespace": "REDACTED"},
+						"cidrBlocks":                 []any{"REDACTED"},
+					},
+					"status": map[string]any{
+						"arn":     "REDACTED",
+						"message": "account REDACTED is not allowed from REDACTED",
+						"count":   int64(3),
+					},
+				},
+			},
+		},
+		"Configured": {
+			reason: "Should only use configured fields and patterns if built-in rules are disabled",
+			args: args{
+				cfg: Config{
+					Enabled:        true,
+					DisableBuiltin: true,
+					Fields:         []string{"spec.forProvider.tags[*]"},
+					Patterns:       []string{`team-\w+`},
+					Replacement:    "***",
+				},
+				obj: map[string]any{
+					"spec": map[string]any{
+						"forProvider": map[string]any{
+							"tags":   map[string]any{"owner": "alice", "env": "prod"},
+							"region": "us-east-1",
+						},
+					},
+					"metadata": map[string]any{"name": "team-platform-10.0.0.1"},
+				},
+			},
+			want: want{
+				obj: map[string]any{
+					"spec": map[string]any{
+						"forProvider": map[string]any{
+							"tags":   map[string]any{"owner": "***", "env": "***"},
+							"region": "us-east-1",
+						},
+					},
+					"metadata": map[string]any{"name": "***-10.0.0.1"},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := New(tc.args.cfg)
+			if err != nil {
+				t.Fatalf("%s\nNew() error = %v", tc.reason, err)
+			}
+			if got := r.Object(tc.args.obj); !reflect.DeepEqual(got, tc.want.obj) {
+				t.Errorf("%s\nObject() = %v, want %v", tc.reason, got, tc.want.obj)
+			}
+		})
+	}
+}
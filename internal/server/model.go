@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+)
+
+// Node is a resource of the trace, along with its computed status
+type Node struct {
+	Object   map[string]any        `json:"object"`
+	Status   xplane.ResourceStatus `json:"status"`
+	Error    string                `json:"error,omitempty"`
+	Children []Node                `json:"children"`
+}
+
+// Snapshot is the latest trace, or the error which prevented getting it
+type Snapshot struct {
+	Trace     *Node     `json:"trace,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func newNode(r *xplane.Resource) Node {
+	name := fmt.Sprintf("%s/%s", r.Unstructured.GetKind(), r.Unstructured.GetName())
+	n := Node{
+		Object:   r.Unstructured.Object,
+		Status:   xplane.GetResourceStatus(r, name),
+		Children: make([]Node, 0, len(r.Children)),
+	}
+	if r.Error != nil {
+		n.Error = r.Error.Error()
+	}
+	for _, c := range r.Children {
+		n.Children = append(n.Children, newNode(c))
+	}
+	return n
+}
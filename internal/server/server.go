@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+)
+
+//go:embed static
+var static embed.FS
+
+// keepAlive is how often idle streams get a comment, so proxies keep them open
+const keepAlive = 15 * time.Second
+
+type Tracer interface {
+	GetTrace(ctx context.Context) (*xplane.Resource, error)
+}
+
+// Server exposes the latest trace over HTTP, refreshing it periodically
+type Server struct {
+	tracer   Tracer
+	interval time.Duration
+	logger   *slog.Logger
+	redactor *redact.Redactor
+
+	mu          sync.RWMutex
+	snapshot    []byte
+	content     []byte // snapshot without its timestamp, to detect changes
+	subscribers map[chan []byte]struct{}
+}
+
+type WithOpt func(*Server)
+
+func WithInterval(t time.Duration) func(*Server) {
+	return func(s *Server) {
+		s.interval = t
+	}
+}
+
+// WithRedactor redacts every snapshot before it is published
+func WithRedactor(r *redact.Redactor) func(*Server) {
+	return func(s *Server) {
+		s.redactor = r
+	}
+}
+
+func New(logger *slog.Logger, tracer Tracer, opts ...WithOpt) *Server {
+	s := &Server{
+		tracer:      tracer,
+		interval:    10 * time.Second,
+		logger:      logger,
+		subscribers: map[chan []byte]struct{}{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Handler serves the web page, the JSON API and the updates stream
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.onIndex)
+	mux.HandleFunc("GET /api/trace", s.onTrace)
+	mux.HandleFunc("GET /api/stream", s.onStream)
+	return mux
+}
+
+// Run refreshes the trace until the context is cancelled, notifying streams
+// whenever it changes
+func (s *Server) Run(ctx context.Context) {
+	s.refresh(ctx)
+
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+func (s *Server) refresh(ctx context.Context) {
+	snapshot := Snapshot{}
+	trace, err := s.tracer.GetTrace(ctx)
+	switch {
+	case err != nil:
+		s.logger.Warn("failed to refresh trace", "err", err)
+		snapshot.Error = s.redactor.String(err.Error())
+	case trace == nil:
+		snapshot.Error = "trace is empty"
+	default:
+		n := newNode(s.redactor.Resource(trace))
+		snapshot.Trace = &n
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		s.logger.Error("failed to encode trace", "err", err)
+		return
+	}
+	snapshot.UpdatedAt = time.Now()
+	data, _ := json.Marshal(snapshot)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshot = data
+	if bytes.Equal(content, s.content) {
+		return
+	}
+	s.content = content
+	for ch := range s.subscribers {
+		publish(ch, data)
+	}
+}
+
+// publish replaces any update the subscriber has not received yet, as only
+// the latest one matters
+func publish(ch chan []byte, data []byte) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- data
+}
+
+func (s *Server) latest() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snapshot
+}
+
+func (s *Server) onIndex(w http.ResponseWriter, r *http.Request) {
+	http.ServeFileFS(w, r, static, "static/index.html")
+}
+
+func (s *Server) onTrace(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	data := s.latest()
+	if data == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(Snapshot{Error: "trace not loaded yet"})
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func (s *Server) onStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan []byte, 1)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	if s.snapshot != nil {
+		publish(ch, s.snapshot)
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	t := time.NewTicker(keepAlive)
+	defer t.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-t.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case data := <-ch:
+			_, err = fmt.Fprintf(w, "event: trace\ndata: %s\n\n", data)
+		}
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				s.logger.Debug("stream closed", "err", err)
+			}
+			return
+		}
+		flusher.Flush()
+	}
+}
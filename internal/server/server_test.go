@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brunoluiz/crossplane-explorer/internal/redact"
+	"github.com/brunoluiz/crossplane-explorer/internal/xplane"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type fakeTracer struct {
+	mu   sync.Mutex
+	name string
+}
+
+func (t *fakeTracer) setName(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.name = name
+}
+
+func (t *fakeTracer) GetTrace(_ context.Context) (*xplane.Resource, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &xplane.Resource{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "XBucket",
+		"metadata":   map[string]any{"name": t.name},
+	}}}, nil
+}
+
+func TestServer(t *testing.T) {
+	tracer := &fakeTracer{name: "first"}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), tracer, WithInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.refresh(ctx)
+
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/api/trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(res.Body).Decode(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Trace == nil || snapshot.Trace.Status.Name != "XBucket/first" {
+		t.Fatalf("Should serve the trace with its status\nGET /api/trace = %+v", snapshot)
+	}
+
+	stream, err := http.Get(srv.URL + "/api/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	names := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stream.Body)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var s Snapshot
+			if err := json.Unmarshal([]byte(data), &s); err == nil && s.Trace != nil {
+				names <- s.Trace.Status.Name
+			}
+		}
+	}()
+
+	for i, want := range []string{"XBucket/first", "XBucket/second"} {
+		if i == 1 {
+			tracer.setName("second")
+			go s.Run(ctx)
+		}
+
+		select {
+		case got := <-names:
+			if got != want {
+				t.Errorf("Should stream the current trace and then its changes\nGET /api/stream = %q, want %q", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Should stream %q, but timed out", want)
+		}
+	}
+}
+
+func TestServerRedaction(t *testing.T) {
+	redactor, err := redact.New(redact.Config{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := &fakeTracer{name: "bucket-123456789012"}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), tracer, WithRedactor(redactor))
+	s.refresh(context.Background())
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(s.latest(), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "bucket-" + redact.DefaultReplacement
+	if snapshot.Trace == nil || snapshot.Trace.Status.Name != "XBucket/"+want {
+		t.Fatalf("Should redact the status of the published trace\nsnapshot = %+v", snapshot)
+	}
+	if got := snapshot.Trace.Object["metadata"].(map[string]any)["name"]; got != want {
+		t.Errorf("Should redact the object of the published trace\nmetadata.name = %v, want %v", got, want)
+	}
+}
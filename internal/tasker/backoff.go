@@ -0,0 +1,13 @@
+package tasker
+
+import "time"
+
+// Backoff returns an exponential delay for a certain retry attempt (starting
+// at 1), doubling base on every attempt up to limit.
+func Backoff(base, limit time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
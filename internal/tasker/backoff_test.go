@@ -0,0 +1,44 @@
+package tasker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	type args struct {
+		attempt int
+	}
+
+	type want struct {
+		delay time.Duration
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"First": {
+			reason: "Should wait the base delay on the first attempt",
+			args:   args{attempt: 1},
+			want:   want{delay: time.Second},
+		},
+		"Doubles": {
+			reason: "Should double the delay on every attempt",
+			args:   args{attempt: 3},
+			want:   want{delay: 4 * time.Second},
+		},
+		"Limit": {
+			reason: "Should stop at the limit",
+			args:   args{attempt: 10},
+			want:   want{delay: 10 * time.Second},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Backoff(time.Second, 10*time.Second, tc.args.attempt); got != tc.want.delay {
+				t.Errorf("%s\nBackoff(1s, 10s, %d) = %s, want %s", tc.reason, tc.args.attempt, got, tc.want.delay)
+			}
+		})
+	}
+}
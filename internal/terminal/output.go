@@ -0,0 +1,30 @@
+// Package terminal shares the terminal between the program renderer and
+// anything else writing escape sequences into it, such as OSC52 or bells.
+package terminal
+
+import (
+	"os"
+	"sync"
+)
+
+// Output is a terminal whose writes are serialised, so sequences written from
+// other goroutines don't end up in the middle of a rendered frame. It is still
+// a file, so the program can tell it is a terminal and watch its size.
+type Output struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func NewOutput(f *os.File) *Output {
+	return &Output{File: f}
+}
+
+func (o *Output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(p)
+}
+
+func (o *Output) WriteString(s string) (int, error) {
+	return o.Write([]byte(s))
+}
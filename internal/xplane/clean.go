@@ -0,0 +1,60 @@
+package xplane
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DefaultNoiseFields are fields that rarely help while debugging a trace and
+// tend to push the interesting bits off screen
+var DefaultNoiseFields = []string{
+	"metadata.managedFields",
+	"metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.selfLink",
+}
+
+// NewFieldStripper returns a function which copies an object without the
+// given field paths. Paths follow the crossplane field path syntax and accept
+// wildcards, such as `status.conditions[*].lastTransitionTime`.
+func NewFieldStripper(paths []string) func(map[string]any) map[string]any {
+	return func(obj map[string]any) map[string]any {
+		p := fieldpath.Pave(runtime.DeepCopyJSON(obj))
+		for _, path := range paths {
+			expanded, err := p.ExpandWildcards(path)
+			if err != nil {
+				continue
+			}
+			for _, e := range expanded {
+				//nolint // missing fields are fine
+				p.DeleteField(e)
+				pruneEmptyParents(p, e)
+			}
+		}
+		return p.UnstructuredContent()
+	}
+}
+
+// pruneEmptyParents removes objects left empty after a field got deleted, so
+// the output doesn't end up with things such as `annotations: {}`
+func pruneEmptyParents(p *fieldpath.Paved, path string) {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return
+	}
+
+	for i := len(segments) - 1; i > 0; i-- {
+		parent := segments[:i].String()
+		v, err := p.GetValue(parent)
+		if err != nil {
+			return
+		}
+		if m, ok := v.(map[string]any); !ok || len(m) > 0 {
+			return
+		}
+		if err := p.DeleteField(parent); err != nil {
+			return
+		}
+	}
+}
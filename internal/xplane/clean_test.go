@@ -0,0 +1,97 @@
+package xplane
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldStripper(t *testing.T) {
+	newObj := func() map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{
+				"name":            "bucket",
+				"resourceVersion": "42",
+				"annotations": map[string]any{
+					"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				},
+				"labels": map[string]any{"team": "platform"},
+			},
+			"status": map[string]any{
+				"conditions": []any{
+					map[string]any{"type": "Synced", "lastTransitionTime": "2025-01-01T00:00:00Z"},
+					map[string]any{"type": "Ready", "lastTransitionTime": "2025-01-01T00:00:00Z"},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		paths []string
+	}
+
+	type want struct {
+		obj map[string]any
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Field": {
+			reason: "Should remove the given fields",
+			args:   args{paths: []string{"metadata.resourceVersion"}},
+			want: want{obj: func() map[string]any {
+				o := newObj()
+				delete(o["metadata"].(map[string]any), "resourceVersion")
+				return o
+			}()},
+		},
+		"Wildcard": {
+			reason: "Should remove fields matching wildcards",
+			args:   args{paths: []string{"status.conditions[*].lastTransitionTime"}},
+			want: want{obj: func() map[string]any {
+				o := newObj()
+				o["status"] = map[string]any{"conditions": []any{
+					map[string]any{"type": "Synced"},
+					map[string]any{"type": "Ready"},
+				}}
+				return o
+			}()},
+		},
+		"Missing": {
+			reason: "Should ignore fields which are not set",
+			args:   args{paths: []string{"spec.forProvider.region", "status.atProvider[*].arn"}},
+			want:   want{obj: newObj()},
+		},
+		"PruneEmptyParents": {
+			reason: "Should remove objects left empty, but not their non-empty parents",
+			args:   args{paths: []string{"metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]"}},
+			want: want{obj: func() map[string]any {
+				o := newObj()
+				delete(o["metadata"].(map[string]any), "annotations")
+				return o
+			}()},
+		},
+		"PruneUpToRoot": {
+			reason: "Should remove all parents left empty",
+			args:   args{paths: []string{"status.conditions"}},
+			want: want{obj: func() map[string]any {
+				o := newObj()
+				delete(o, "status")
+				return o
+			}()},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := newObj()
+			got := NewFieldStripper(tc.args.paths)(obj)
+			if !reflect.DeepEqual(got, tc.want.obj) {
+				t.Errorf("%s\nNewFieldStripper(...) = %v, want %v", tc.reason, got, tc.want.obj)
+			}
+			if !reflect.DeepEqual(obj, newObj()) {
+				t.Errorf("%s\nNewFieldStripper(...) should not change the original, got %v", tc.reason, obj)
+			}
+		})
+	}
+}
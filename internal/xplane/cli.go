@@ -0,0 +1,88 @@
+package xplane
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/shlex"
+)
+
+// cliCommand is a command line used by CLI queriers, along with the extra
+// environment it runs with
+type cliCommand struct {
+	app     string
+	args    []string
+	env     []string
+	timeout time.Duration
+}
+
+// CLIOpt configures how CLI queriers run their commands
+type CLIOpt func(c *cliCommand)
+
+// WithEnv adds environment variables to the commands, such as KUBECONFIG
+func WithEnv(env []string) CLIOpt {
+	return func(c *cliCommand) {
+		c.env = append(c.env, env...)
+	}
+}
+
+// WithTimeout limits how long each command can run for
+func WithTimeout(t time.Duration) CLIOpt {
+	return func(c *cliCommand) {
+		c.timeout = t
+	}
+}
+
+// newCLICommand parses a command line following the shell quoting rules
+func newCLICommand(cmd string, opts ...CLIOpt) (cliCommand, error) {
+	s, err := shlex.Split(cmd)
+	if err != nil {
+		return cliCommand{}, fmt.Errorf("failed to parse command %q: %w", cmd, err)
+	}
+	if len(s) == 0 {
+		return cliCommand{}, errors.New("command is empty")
+	}
+
+	c := cliCommand{app: s[0], args: s[1:]}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c, nil
+}
+
+// output runs the command with extra arguments appended, returning its
+// stdout. On failure, stderr is included in the error.
+func (c cliCommand) output(ctx context.Context, args ...string) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	//nolint // trust the user input
+	cmd := exec.CommandContext(ctx, c.app, append(append([]string{}, c.args...), args...)...)
+	if len(c.env) > 0 {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %s", c.app, c.timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %w: %s", c.app, err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %w", c.app, err)
+	}
+
+	return stdout, nil
+}
@@ -0,0 +1,85 @@
+package xplane
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCLICommandOutput(t *testing.T) {
+	type args struct {
+		cmd  string
+		opts []CLIOpt
+	}
+
+	type want struct {
+		output string
+		err    string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"QuotedArgs": {
+			reason: "Should keep quoted arguments together",
+			args: args{
+				cmd: `sh -c 'echo "$0 $1"' "hello world" again`,
+			},
+			want: want{output: "hello world again\n"},
+		},
+		"Stderr": {
+			reason: "Should include stderr in errors",
+			args: args{
+				cmd: `sh -c 'echo boom >&2; exit 1'`,
+			},
+			want: want{err: "sh failed: exit status 1: boom"},
+		},
+		"Timeout": {
+			reason: "Should stop commands running for too long",
+			args: args{
+				cmd:  "sleep 5",
+				opts: []CLIOpt{WithTimeout(10 * time.Millisecond)},
+			},
+			want: want{err: "sleep timed out after 10ms"},
+		},
+		"Env": {
+			reason: "Should add environment variables",
+			args: args{
+				cmd:  `sh -c 'echo $KUBECONFIG'`,
+				opts: []CLIOpt{WithEnv([]string{"KUBECONFIG=/tmp/config"})},
+			},
+			want: want{output: "/tmp/config\n"},
+		},
+		"Unbalanced": {
+			reason: "Should fail to parse commands with unbalanced quotes",
+			args: args{
+				cmd: `sh -c 'echo`,
+			},
+			want: want{err: "failed to parse command"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := newCLICommand(tc.args.cmd, tc.args.opts...)
+			var out []byte
+			if err == nil {
+				out, err = c.output(context.Background())
+			}
+
+			if tc.want.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.want.err) {
+					t.Errorf("%s\noutput() error = %v, want %q", tc.reason, err, tc.want.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s\noutput() error = %v", tc.reason, err)
+			}
+			if string(out) != tc.want.output {
+				t.Errorf("%s\noutput() = %q, want %q", tc.reason, out, tc.want.output)
+			}
+		})
+	}
+}
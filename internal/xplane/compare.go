@@ -0,0 +1,208 @@
+package xplane
+
+import (
+	"strings"
+)
+
+// Change describes how a resource differs between two traces
+type Change string
+
+const (
+	ChangeNone    Change = ""
+	ChangeStatus  Change = "status"
+	ChangeMissing Change = "missing"
+	ChangeExtra   Change = "extra"
+)
+
+// Comparison aligns a resource found in two traces, along with its children.
+// Left is nil if the resource is only found in the right trace (extra), while
+// Right is nil if it is missing from it.
+type Comparison struct {
+	Left     *Resource
+	Right    *Resource
+	Children []*Comparison
+}
+
+// Compare aligns two traces. Children are matched by kind and composition
+// resource name, as names tend to be generated and differ between clusters.
+// Children sharing both are matched in order.
+func Compare(left, right *Resource) *Comparison {
+	c := &Comparison{Left: left, Right: right}
+
+	var lc, rc []*Resource
+	if left != nil {
+		lc = left.Children
+	}
+	if right != nil {
+		rc = right.Children
+	}
+
+	matched := make([]bool, len(rc))
+	for _, l := range lc {
+		var match *Resource
+		for i, r := range rc {
+			if !matched[i] && alignKey(l) == alignKey(r) {
+				matched[i] = true
+				match = r
+				break
+			}
+		}
+		c.Children = append(c.Children, Compare(l, match))
+	}
+	for i, r := range rc {
+		if !matched[i] {
+			c.Children = append(c.Children, Compare(nil, r))
+		}
+	}
+
+	return c
+}
+
+// Resource returns the left resource or, if there is none, the right one
+func (c *Comparison) Resource() *Resource {
+	if c.Left != nil {
+		return c.Left
+	}
+	return c.Right
+}
+
+// Change returns how the resource differs, only taking its status into account
+func (c *Comparison) Change() Change {
+	switch {
+	case c.Right == nil:
+		return ChangeMissing
+	case c.Left == nil:
+		return ChangeExtra
+	}
+
+	l, r := GetResourceStatus(c.Left, ""), GetResourceStatus(c.Right, "")
+	if l.Synced != r.Synced || l.Ready != r.Ready {
+		return ChangeStatus
+	}
+	return ChangeNone
+}
+
+// Changes counts the changes in the comparison and all its children
+func (c *Comparison) Changes() map[Change]int {
+	changes := map[Change]int{}
+	c.countChanges(changes)
+	return changes
+}
+
+func (c *Comparison) countChanges(changes map[Change]int) {
+	changes[c.Change()]++
+	for _, cc := range c.Children {
+		cc.countChanges(changes)
+	}
+}
+
+func alignKey(r *Resource) string {
+	gk := r.Unstructured.GroupVersionKind().GroupKind()
+	return gk.String() + "/" + r.Unstructured.GetAnnotations()["crossplane.io/composition-resource-name"]
+}
+
+// LineOp tells whether a line is kept, removed or added by a diff
+type LineOp int
+
+const (
+	LineEqual LineOp = iota
+	LineRemoved
+	LineAdded
+)
+
+// Line is a line of a diff
+type Line struct {
+	Op   LineOp
+	Text string
+}
+
+// maxDiffCells caps the size of the table used to align changed lines, as it
+// grows with the product of their number. Beyond it, changed lines are shown
+// as removed and then added, without aligning the common ones among them.
+const maxDiffCells = 1 << 20
+
+// DiffLines returns the line by line differences between two texts, based on
+// their longest common subsequence
+func DiffLines(a, b string) []Line {
+	al, bl := splitLines(a), splitLines(b)
+
+	// Only the lines between the common prefix and suffix need to be aligned
+	prefix := 0
+	for prefix < len(al) && prefix < len(bl) && al[prefix] == bl[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(al)-prefix && suffix < len(bl)-prefix && al[len(al)-1-suffix] == bl[len(bl)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, max(len(al), len(bl)))
+	for _, l := range al[:prefix] {
+		lines = append(lines, Line{Op: LineEqual, Text: l})
+	}
+	lines = append(lines, alignLines(al[prefix:len(al)-suffix], bl[prefix:len(bl)-suffix])...)
+	for _, l := range al[len(al)-suffix:] {
+		lines = append(lines, Line{Op: LineEqual, Text: l})
+	}
+
+	return lines
+}
+
+func alignLines(al, bl []string) []Line {
+	lines := make([]Line, 0, max(len(al), len(bl)))
+	if len(al)*len(bl) > maxDiffCells {
+		for _, l := range al {
+			lines = append(lines, Line{Op: LineRemoved, Text: l})
+		}
+		for _, l := range bl {
+			lines = append(lines, Line{Op: LineAdded, Text: l})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of al[i:] and bl[j:]
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(al) && j < len(bl) {
+		switch {
+		case al[i] == bl[j]:
+			lines = append(lines, Line{Op: LineEqual, Text: al[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: LineRemoved, Text: al[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: LineAdded, Text: bl[j]})
+			j++
+		}
+	}
+	for ; i < len(al); i++ {
+		lines = append(lines, Line{Op: LineRemoved, Text: al[i]})
+	}
+	for ; j < len(bl); j++ {
+		lines = append(lines, Line{Op: LineAdded, Text: bl[j]})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,160 @@
+package xplane
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// flattenComparison lists every aligned pair as change:left/right
+func flattenComparison(c *Comparison) []string {
+	name := func(r *Resource) string {
+		if r == nil {
+			return "-"
+		}
+		return r.Unstructured.GetName()
+	}
+
+	pairs := []string{string(c.Change()) + ":" + name(c.Left) + "/" + name(c.Right)}
+	for _, cc := range c.Children {
+		pairs = append(pairs, flattenComparison(cc)...)
+	}
+	return pairs
+}
+
+func TestCompare(t *testing.T) {
+	type args struct {
+		left  *Resource
+		right *Resource
+	}
+
+	type want struct {
+		pairs []string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ResourceName": {
+			reason: "Should align children by kind and composition resource name, regardless of their names",
+			args: args{
+				left: newComposedResource("XBucket", "staging", "", "True",
+					newComposedResource("Bucket", "staging-abc", "bucket", "True"),
+					newComposedResource("Policy", "staging-def", "policy", "True"),
+				),
+				right: newComposedResource("XBucket", "production", "", "True",
+					newComposedResource("Policy", "production-uvw", "policy", "False"),
+					newComposedResource("Bucket", "production-xyz", "bucket", "True"),
+				),
+			},
+			want: want{pairs: []string{
+				":staging/production",
+				":staging-abc/production-xyz",
+				"status:staging-def/production-uvw",
+			}},
+		},
+		"MissingAndExtra": {
+			reason: "Should report resources only found on one side, along with their children",
+			args: args{
+				left: newComposedResource("XBucket", "root", "", "True",
+					newComposedResource("Bucket", "bucket", "bucket", "True"),
+					newComposedResource("XNetwork", "network", "network", "True",
+						newComposedResource("VPC", "vpc", "vpc", "True"),
+					),
+				),
+				right: newComposedResource("XBucket", "root", "", "True",
+					newComposedResource("Bucket", "bucket", "bucket", "True"),
+					newComposedResource("Bucket", "logs", "logs", "True"),
+				),
+			},
+			want: want{pairs: []string{
+				":root/root",
+				":bucket/bucket",
+				"missing:network/-",
+				"missing:vpc/-",
+				"extra:-/logs",
+			}},
+		},
+		"SameKey": {
+			reason: "Should align children sharing kind and composition resource name in order",
+			args: args{
+				left: newComposedResource("XBucket", "root", "", "True",
+					newComposedResource("Bucket", "a", "", "True"),
+					newComposedResource("Bucket", "b", "", "True"),
+				),
+				right: newComposedResource("XBucket", "root", "", "True",
+					newComposedResource("Bucket", "c", "", "True"),
+				),
+			},
+			want: want{pairs: []string{":root/root", ":a/c", "missing:b/-"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := flattenComparison(Compare(tc.args.left, tc.args.right))
+			if !slices.Equal(got, tc.want.pairs) {
+				t.Errorf("%s\nCompare() = %v, want %v", tc.reason, got, tc.want.pairs)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+
+	type want struct {
+		lines []Line
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Equal": {
+			reason: "Should keep equal lines",
+			args:   args{a: "a\nb\n", b: "a\nb\n"},
+			want:   want{lines: []Line{{LineEqual, "a"}, {LineEqual, "b"}}},
+		},
+		"Changed": {
+			reason: "Should replace changed lines, keeping the common ones",
+			args:   args{a: "a\nb\nc\n", b: "a\nx\nc\nd\n"},
+			want: want{lines: []Line{
+				{LineEqual, "a"},
+				{LineRemoved, "b"},
+				{LineAdded, "x"},
+				{LineEqual, "c"},
+				{LineAdded, "d"},
+			}},
+		},
+		"TooLarge": {
+			reason: "Should remove and then add changed lines which are too many to be aligned",
+			args: args{
+				a: "a\n" + strings.Repeat("b\nc\n", 1024) + "z\n",
+				b: "a\n" + strings.Repeat("c\nd\n", 1024) + "z\n",
+			},
+			want: want{lines: slices.Concat(
+				[]Line{{LineEqual, "a"}},
+				slices.Repeat([]Line{{LineRemoved, "b"}, {LineRemoved, "c"}}, 1024),
+				slices.Repeat([]Line{{LineAdded, "c"}, {LineAdded, "d"}}, 1024),
+				[]Line{{LineEqual, "z"}},
+			)},
+		},
+		"Empty": {
+			reason: "Should remove all lines when compared to nothing",
+			args:   args{a: "a\nb", b: ""},
+			want:   want{lines: []Line{{LineRemoved, "a"}, {LineRemoved, "b"}}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := DiffLines(tc.args.a, tc.args.b)
+			if !slices.Equal(got, tc.want.lines) {
+				t.Errorf("%s\nDiffLines() = %v, want %v", tc.reason, got, tc.want.lines)
+			}
+		})
+	}
+}
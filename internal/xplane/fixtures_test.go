@@ -0,0 +1,44 @@
+package xplane
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// reportStart is when fixtures are created, unless set otherwise
+var reportStart = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// newComposedResource returns a fixture with a Ready condition (unless ready
+// is empty), annotated with its composition resource name if given. All
+// fixtures build upon it.
+func newComposedResource(kind, name, resourceName, ready string, children ...*Resource) *Resource {
+	r := &Resource{
+		Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name},
+		}},
+		Children: children,
+	}
+	if resourceName != "" {
+		r.Unstructured.SetAnnotations(map[string]string{"crossplane.io/composition-resource-name": resourceName})
+	}
+	if ready != "" {
+		setCondition(r, "Ready", ready, time.Time{})
+	}
+	return r
+}
+
+// setCondition adds a condition to a fixture, with its last transition time
+// if it is not zero
+func setCondition(r *Resource, ct, status string, lastTransition time.Time) {
+	c := map[string]any{"type": ct, "status": status}
+	if !lastTransition.IsZero() {
+		c["lastTransitionTime"] = lastTransition.Format(time.RFC3339)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(r.Unstructured.Object, "status", "conditions")
+	//nolint // fixtures only hold JSON values
+	unstructured.SetNestedSlice(r.Unstructured.Object, append(conditions, c), "status", "conditions")
+}
@@ -21,6 +21,18 @@ type Resource struct {
 	Children     []*Resource               `json:"children,omitempty"`
 }
 
+// Key returns an identifier for the resource which is stable across traces,
+// made of its API version, kind, namespace and name.
+func (r *Resource) Key() string {
+	return fmt.Sprintf(
+		"%s/%s/%s/%s",
+		r.Unstructured.GetAPIVersion(),
+		r.Unstructured.GetKind(),
+		r.Unstructured.GetNamespace(),
+		r.Unstructured.GetName(),
+	)
+}
+
 // GetCondition of this resource.
 func (r *Resource) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	conditioned := xpv1.ConditionedStatus{}
@@ -38,15 +50,24 @@ func (r *Resource) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return xpv1.Condition{}
 }
 
+// GetConditions returns all conditions reported by this resource.
+func (r *Resource) GetConditions() []xpv1.Condition {
+	conditioned := xpv1.ConditionedStatus{}
+	if err := fieldpath.Pave(r.Unstructured.Object).GetValueInto("status", &conditioned); err != nil {
+		return nil
+	}
+	return conditioned.Conditions
+}
+
 type ResourceStatus struct {
-	Name                 string
-	ResourceName         string
-	Ready                string
-	ReadyLastTransition  time.Time
-	Synced               string
-	SyncedLastTransition time.Time
-	Status               string
-	Ok                   bool
+	Name                 string    `json:"name"`
+	ResourceName         string    `json:"resourceName,omitempty"`
+	Ready                string    `json:"ready"`
+	ReadyLastTransition  time.Time `json:"readyLastTransition"`
+	Synced               string    `json:"synced"`
+	SyncedLastTransition time.Time `json:"syncedLastTransition"`
+	Status               string    `json:"status"`
+	Ok                   bool      `json:"ok"`
 }
 
 // getResourceStatus returns a string that represents an entire row of status
@@ -214,3 +235,28 @@ func mapEmptyStatusToDash(s corev1.ConditionStatus) string {
 	}
 	return string(s)
 }
+
+// Replace returns a copy of the tree in which the object of the resource with
+// the same Key as r is replaced by r's, keeping its children. Resources which
+// are not on the path to the replaced one are shared with the original tree.
+func Replace(root, r *Resource) (*Resource, bool) {
+	if root == nil {
+		return nil, false
+	}
+
+	if root.Key() == r.Key() {
+		c := *root
+		c.Unstructured = r.Unstructured
+		return &c, true
+	}
+
+	for i, child := range root.Children {
+		if replaced, ok := Replace(child, r); ok {
+			c := *root
+			c.Children = append([]*Resource{}, root.Children...)
+			c.Children[i] = replaced
+			return &c, true
+		}
+	}
+	return root, false
+}
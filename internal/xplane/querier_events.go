@@ -0,0 +1,55 @@
+package xplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CLIEventsQuerier defines an events querier using the kubectl CLI
+type CLIEventsQuerier struct {
+	cmd cliCommand
+}
+
+func NewCLIEventsQuerier(cmd string, opts ...CLIOpt) (*CLIEventsQuerier, error) {
+	c, err := newCLICommand(cmd, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &CLIEventsQuerier{cmd: c}, nil
+}
+
+// GetEvents returns all events related to a certain traced resource
+func (q *CLIEventsQuerier) GetEvents(ctx context.Context, r *Resource) ([]corev1.Event, error) {
+	selector := []string{
+		"involvedObject.kind=" + r.Unstructured.GetKind(),
+		"involvedObject.name=" + r.Unstructured.GetName(),
+	}
+	if uid := r.Unstructured.GetUID(); uid != "" {
+		selector = append(selector, "involvedObject.uid="+string(uid))
+	}
+
+	args := []string{}
+	if ns := r.Unstructured.GetNamespace(); ns != "" {
+		args = append(args, "--namespace", ns)
+	} else {
+		// Events for cluster scoped objects might be in any namespace
+		args = append(args, "--all-namespaces")
+	}
+	args = append(args, "--field-selector", strings.Join(selector, ","))
+
+	stdout, err := q.cmd.output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list corev1.EventList
+	if err := json.Unmarshal(stdout, &list); err != nil {
+		return nil, fmt.Errorf("Failed to decode JSON: %w", err)
+	}
+
+	return list.Items, nil
+}
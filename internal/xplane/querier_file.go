@@ -0,0 +1,52 @@
+package xplane
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileTraceQuerier defines a trace querier reading a JSON trace file, which is
+// only parsed again once its modification time or size changes
+type FileTraceQuerier struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	trace   *Resource
+}
+
+func NewFileTraceQuerier(path string) *FileTraceQuerier {
+	return &FileTraceQuerier{path: path}
+}
+
+func (q *FileTraceQuerier) GetTrace(_ context.Context) (*Resource, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	info, err := os.Stat(q.path)
+	if err != nil {
+		return nil, err
+	}
+	if q.trace != nil && info.ModTime().Equal(q.modTime) && info.Size() == q.size {
+		return q.trace, nil
+	}
+
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The file might be half-written, so the cache is only updated on success
+	trace, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", q.path, err)
+	}
+
+	q.trace, q.modTime, q.size = trace, info.ModTime(), info.Size()
+	return trace, nil
+}
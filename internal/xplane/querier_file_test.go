@@ -0,0 +1,44 @@
+package xplane
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTraceQuerier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	write := func(name string, modTime time.Time) {
+		t.Helper()
+		data := `{"object": {"apiVersion": "v1", "kind": "Bucket", "metadata": {"name": "` + name + `"}}}`
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q := NewFileTraceQuerier(path)
+	now := time.Now()
+	write("first", now)
+
+	first, err := q.GetTrace(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := q.GetTrace(context.Background()); again != first {
+		t.Errorf("Should reuse the trace while the file is unchanged")
+	}
+
+	write("second", now.Add(time.Second))
+	second, err := q.GetTrace(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := second.Unstructured.GetName(); name != "second" {
+		t.Errorf("Should read the file again once it changes\nGetTrace() name = %q, want %q", name, "second")
+	}
+}
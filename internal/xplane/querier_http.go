@@ -0,0 +1,154 @@
+package xplane
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxErrorBody limits how much of an error response gets into errors
+const maxErrorBody = 512
+
+// HTTPTraceQuerier defines a trace querier fetching the JSON trace from a URL,
+// such as an exporter running close to the cluster
+type HTTPTraceQuerier struct {
+	url     string
+	headers http.Header
+	client  *http.Client
+}
+
+type httpOptions struct {
+	headers  http.Header
+	timeout  time.Duration
+	caFile   string
+	certFile string
+	keyFile  string
+	insecure bool
+}
+
+// HTTPOpt configures how traces are fetched
+type HTTPOpt func(o *httpOptions)
+
+// WithHeader adds a header to every request
+func WithHeader(name, value string) HTTPOpt {
+	return func(o *httpOptions) {
+		o.headers.Add(name, value)
+	}
+}
+
+// WithBearerToken authenticates requests with a token, if set
+func WithBearerToken(token string) HTTPOpt {
+	return func(o *httpOptions) {
+		if token != "" {
+			o.headers.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
+// WithHTTPTimeout limits how long each request can take
+func WithHTTPTimeout(t time.Duration) HTTPOpt {
+	return func(o *httpOptions) {
+		o.timeout = t
+	}
+}
+
+// WithCA trusts the certificate authorities in a PEM file, on top of the
+// system ones
+func WithCA(file string) HTTPOpt {
+	return func(o *httpOptions) {
+		o.caFile = file
+	}
+}
+
+// WithClientCert authenticates with a client certificate (mutual TLS)
+func WithClientCert(certFile, keyFile string) HTTPOpt {
+	return func(o *httpOptions) {
+		o.certFile = certFile
+		o.keyFile = keyFile
+	}
+}
+
+// WithInsecureSkipVerify disables the server certificate verification
+func WithInsecureSkipVerify(insecure bool) HTTPOpt {
+	return func(o *httpOptions) {
+		o.insecure = insecure
+	}
+}
+
+func NewHTTPTraceQuerier(url string, opts ...HTTPOpt) (*HTTPTraceQuerier, error) {
+	o := httpOptions{headers: http.Header{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	//nolint // only skipped if explicitly asked for
+	tlsConfig := &tls.Config{InsecureSkipVerify: o.insecure}
+	if o.caFile != "" {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", o.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if o.certFile != "" || o.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &HTTPTraceQuerier{
+		url:     url,
+		headers: o.headers,
+		client:  &http.Client{Transport: transport, Timeout: o.timeout},
+	}, nil
+}
+
+func (q *HTTPTraceQuerier) GetTrace(ctx context.Context) (*Resource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = q.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+
+	res, err := q.client.Do(req)
+	if err != nil {
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("request to %s timed out after %s", q.url, q.client.Timeout)
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBody))
+		return nil, fmt.Errorf("request to %s failed with %s: %s", q.url, res.Status, strings.TrimSpace(string(body)))
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return Parse(bytes.NewReader(body))
+}
@@ -0,0 +1,137 @@
+package xplane
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPTraceQuerier(t *testing.T) {
+	const trace = `{"object": {"apiVersion": "v1", "kind": "Bucket", "metadata": {"name": "bucket"}}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/slow":
+			time.Sleep(100 * time.Millisecond)
+		case r.Header.Get("Authorization") != "" && r.Header.Get("Authorization") != "Bearer secret":
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		case r.Header.Get("X-Tenant") != "" && r.Header.Get("X-Tenant") != "acme":
+			http.Error(w, "unknown tenant", http.StatusForbidden)
+			return
+		case r.URL.Path == "/missing":
+			http.Error(w, "no such trace", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(trace))
+	})
+
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: secure.Certificate().Raw})
+	if err := os.WriteFile(ca, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		url  string
+		opts []HTTPOpt
+	}
+
+	type want struct {
+		name string
+		err  string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"OK": {
+			reason: "Should parse the trace served by the endpoint",
+			args:   args{url: plain.URL},
+			want:   want{name: "bucket"},
+		},
+		"Headers": {
+			reason: "Should send the bearer token and headers",
+			args: args{
+				url:  plain.URL,
+				opts: []HTTPOpt{WithBearerToken("secret"), WithHeader("X-Tenant", "acme")},
+			},
+			want: want{name: "bucket"},
+		},
+		"Unauthorized": {
+			reason: "Should include the status and body in errors",
+			args: args{
+				url:  plain.URL,
+				opts: []HTTPOpt{WithBearerToken("wrong")},
+			},
+			want: want{err: "401 Unauthorized: invalid token"},
+		},
+		"NotFound": {
+			reason: "Should fail when the trace is not found",
+			args:   args{url: plain.URL + "/missing"},
+			want:   want{err: "404 Not Found: no such trace"},
+		},
+		"Timeout": {
+			reason: "Should stop requests taking too long",
+			args: args{
+				url:  plain.URL + "/slow",
+				opts: []HTTPOpt{WithHTTPTimeout(10 * time.Millisecond)},
+			},
+			want: want{err: "timed out after 10ms"},
+		},
+		"UnknownCA": {
+			reason: "Should verify the server certificate",
+			args:   args{url: secure.URL},
+			want:   want{err: "certificate"},
+		},
+		"CA": {
+			reason: "Should trust the given certificate authorities",
+			args: args{
+				url:  secure.URL,
+				opts: []HTTPOpt{WithCA(ca)},
+			},
+			want: want{name: "bucket"},
+		},
+		"Insecure": {
+			reason: "Should skip verifying the server certificate if asked for",
+			args: args{
+				url:  secure.URL,
+				opts: []HTTPOpt{WithInsecureSkipVerify(true)},
+			},
+			want: want{name: "bucket"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, err := NewHTTPTraceQuerier(tc.args.url, tc.args.opts...)
+			if err != nil {
+				t.Fatalf("%s\nNewHTTPTraceQuerier() error = %v", tc.reason, err)
+			}
+
+			res, err := q.GetTrace(context.Background())
+			if tc.want.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.want.err) {
+					t.Errorf("%s\nGetTrace() error = %v, want %q", tc.reason, err, tc.want.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s\nGetTrace() error = %v", tc.reason, err)
+			}
+			if got := res.Unstructured.GetName(); got != tc.want.name {
+				t.Errorf("%s\nGetTrace() name = %q, want %q", tc.reason, got, tc.want.name)
+			}
+		})
+	}
+}
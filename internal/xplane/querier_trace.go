@@ -2,35 +2,37 @@ package xplane
 
 import (
 	"bytes"
+	"context"
 	"io"
-	"os/exec"
-	"strings"
+	"sync"
 )
 
 // CLITraceQuerier defines a trace querier using the crossplane CLI
 type CLITraceQuerier struct {
-	app  string
+	cmd  cliCommand
 	args []string
 }
 
-func NewCLITraceQuerier(cmd string, namespace string, name string) *CLITraceQuerier {
-	s := strings.Split(cmd, " ")
-	app := s[0]
-	args := s[1:]
+func NewCLITraceQuerier(cmd string, namespace string, name string, opts ...CLIOpt) (*CLITraceQuerier, error) {
+	c, err := newCLICommand(cmd, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{}
 	if namespace != "" {
 		args = append(args, "--namespace", namespace)
 	}
 	args = append(args, name)
 
 	return &CLITraceQuerier{
-		app:  app,
+		cmd:  c,
 		args: args,
-	}
+	}, nil
 }
 
-func (q *CLITraceQuerier) GetTrace() (*Resource, error) {
-	//nolint // trust the user input
-	stdout, err := exec.Command(q.app, q.args...).Output()
+func (q *CLITraceQuerier) GetTrace(ctx context.Context) (*Resource, error) {
+	stdout, err := q.cmd.output(ctx, q.args...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,15 +40,25 @@ func (q *CLITraceQuerier) GetTrace() (*Resource, error) {
 	return Parse(bytes.NewReader(stdout))
 }
 
-// ReaderTraceQuerier defines a trace querier using piped files through stdin
+// ReaderTraceQuerier defines a trace querier using piped files through stdin.
+// Readers such as stdin can only be read once, so their content is kept and
+// parsed again on every refresh.
 type ReaderTraceQuerier struct {
 	r io.Reader
+
+	once sync.Once
+	data []byte
+	err  error
 }
 
 func NewReaderTraceQuerier(r io.Reader) *ReaderTraceQuerier {
 	return &ReaderTraceQuerier{r: r}
 }
 
-func (q *ReaderTraceQuerier) GetTrace() (*Resource, error) {
-	return Parse(q.r)
+func (q *ReaderTraceQuerier) GetTrace(_ context.Context) (*Resource, error) {
+	q.once.Do(func() { q.data, q.err = io.ReadAll(q.r) })
+	if q.err != nil {
+		return nil, q.err
+	}
+	return Parse(bytes.NewReader(q.data))
 }
@@ -0,0 +1,26 @@
+package xplane
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReaderTraceQuerier(t *testing.T) {
+	q := NewReaderTraceQuerier(strings.NewReader(`{"object": {"apiVersion": "v1", "kind": "Bucket", "metadata": {"name": "bucket"}}}`))
+
+	first, err := q.GetTrace(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := q.GetTrace(context.Background())
+	if err != nil {
+		t.Fatalf("Should trace again once the reader is drained\nGetTrace() error = %v", err)
+	}
+	if again == first {
+		t.Errorf("Should return a fresh copy of the trace on every call")
+	}
+	if name := again.Unstructured.GetName(); name != "bucket" {
+		t.Errorf("Should return the same trace on every call\nGetTrace() name = %q, want %q", name, "bucket")
+	}
+}
@@ -0,0 +1,92 @@
+package xplane
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var (
+	jqIterator = regexp.MustCompile(`\[\]`)
+	jqQuoted   = regexp.MustCompile(`\["([^"]*)"\]`)
+)
+
+// Query evaluates an expression against an object and returns the result
+// formatted as YAML, or as plain text for scalar values. Expressions can be a
+// JSONPath template (e.g. `{.status.atProvider.arn}`) or a jq-like path (e.g.
+// `.status.conditions[0].reason` or `.metadata.annotations["a/b"]`).
+func Query(obj map[string]any, expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return "", fmt.Errorf("empty query")
+	}
+
+	jp := jsonpath.New("query")
+	if err := jp.Parse(toJSONPath(expr)); err != nil {
+		return "", fmt.Errorf("invalid query: %w", err)
+	}
+
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return "", err
+	}
+
+	values := []any{}
+	for _, r := range results {
+		for _, v := range r {
+			values = append(values, v.Interface())
+		}
+	}
+
+	switch len(values) {
+	case 0:
+		return "", fmt.Errorf("no results for %s", expr)
+	case 1:
+		return formatQueryValue(values[0])
+	default:
+		return formatQueryValue(values)
+	}
+}
+
+// toJSONPath converts jq-like paths into JSONPath templates, leaving
+// templates untouched
+func toJSONPath(expr string) string {
+	if strings.Contains(expr, "{") {
+		return expr
+	}
+
+	if expr == "." || expr == "$" {
+		return "{$}"
+	}
+
+	if !strings.HasPrefix(expr, ".") && !strings.HasPrefix(expr, "$") && !strings.HasPrefix(expr, "[") {
+		expr = "." + expr
+	}
+	expr = jqIterator.ReplaceAllString(expr, "[*]")
+	expr = jqQuoted.ReplaceAllStringFunc(expr, func(s string) string {
+		// JSONPath in kubernetes requires dots within keys to be escaped
+		key := jqQuoted.FindStringSubmatch(s)[1]
+		return "." + strings.ReplaceAll(key, ".", `\.`)
+	})
+	return "{" + expr + "}"
+}
+
+func formatQueryValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "null", nil
+	case map[string]any, []any:
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(out), "\n"), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
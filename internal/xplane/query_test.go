@@ -0,0 +1,96 @@
+package xplane
+
+import (
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	obj := map[string]any{
+		"metadata": map[string]any{
+			"name": "bucket",
+			"annotations": map[string]any{
+				"crossplane.io/composition-resource-name": "one",
+			},
+		},
+		"status": map[string]any{
+			"atProvider": map[string]any{
+				"arn": "arn:aws:s3:::bucket",
+			},
+			"conditions": []any{
+				map[string]any{"type": "Synced", "status": "True"},
+				map[string]any{"type": "Ready", "status": "False"},
+			},
+		},
+	}
+
+	type args struct {
+		expr string
+	}
+
+	type want struct {
+		result string
+		err    bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"JSONPath": {
+			reason: "Should evaluate JSONPath templates",
+			args:   args{expr: "{.status.atProvider.arn}"},
+			want:   want{result: "arn:aws:s3:::bucket"},
+		},
+		"JSONPathFilter": {
+			reason: "Should evaluate JSONPath filters",
+			args:   args{expr: `{.status.conditions[?(@.type=="Ready")].status}`},
+			want:   want{result: "False"},
+		},
+		"JQPath": {
+			reason: "Should evaluate jq-like paths",
+			args:   args{expr: ".status.atProvider.arn"},
+			want:   want{result: "arn:aws:s3:::bucket"},
+		},
+		"JQPathWithoutLeadingDot": {
+			reason: "Should evaluate jq-like paths without a leading dot",
+			args:   args{expr: "metadata.name"},
+			want:   want{result: "bucket"},
+		},
+		"JQQuotedKey": {
+			reason: "Should evaluate jq-like quoted keys containing dots",
+			args:   args{expr: `.metadata.annotations["crossplane.io/composition-resource-name"]`},
+			want:   want{result: "one"},
+		},
+		"JQIterator": {
+			reason: "Should evaluate jq-like iterators as a YAML list",
+			args:   args{expr: ".status.conditions[].type"},
+			want:   want{result: "- Synced\n- Ready"},
+		},
+		"Object": {
+			reason: "Should render objects as YAML",
+			args:   args{expr: ".status.atProvider"},
+			want:   want{result: "arn: arn:aws:s3:::bucket"},
+		},
+		"Missing": {
+			reason: "Should return an error for missing fields",
+			args:   args{expr: ".spec.forProvider"},
+			want:   want{err: true},
+		},
+		"Empty": {
+			reason: "Should return an error for empty queries",
+			args:   args{expr: " "},
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Query(obj, tc.args.expr)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("%s\nQuery() error = %v, want error %v", tc.reason, err, tc.want.err)
+			}
+			if got != tc.want.result {
+				t.Errorf("%s\nQuery() = %q, want %q", tc.reason, got, tc.want.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package xplane
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// reportSlowest is the number of resources listed as the slowest
+const reportSlowest = 10
+
+// ReportEntry is the provisioning time of a resource, from its creation until
+// it became ready. If it is not ready yet, it is the time elapsed so far.
+type ReportEntry struct {
+	Kind      string        `json:"kind"`
+	Group     string        `json:"group"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Created   time.Time     `json:"created"`
+	ReadyAt   *time.Time    `json:"readyAt,omitempty"`
+	Ready     bool          `json:"ready"`
+	Duration  time.Duration `json:"-"`
+	Seconds   float64       `json:"seconds"`
+}
+
+// ReportAggregate summarises the provisioning times of a set of resources
+type ReportAggregate struct {
+	Name    string        `json:"name"`
+	Count   int           `json:"count"`
+	Ready   int           `json:"ready"`
+	Average time.Duration `json:"-"`
+	Max     time.Duration `json:"-"`
+	AvgSecs float64       `json:"averageSeconds"`
+	MaxSecs float64       `json:"maxSeconds"`
+}
+
+// Report explains where the time to provision a trace was spent. Root is
+// only set if the root of the trace has a creation timestamp.
+type Report struct {
+	GeneratedAt  time.Time         `json:"generatedAt"`
+	Root         *ReportEntry      `json:"root,omitempty"`
+	Skipped      int               `json:"skipped"`
+	Resources    []ReportEntry     `json:"resources"`
+	Slowest      []ReportEntry     `json:"slowest"`
+	ByKind       []ReportAggregate `json:"byKind"`
+	ByGroup      []ReportAggregate `json:"byGroup"`
+	CriticalPath []ReportEntry     `json:"criticalPath"`
+}
+
+// NewReport computes provisioning times for all resources in the trace.
+// Resources without a creation timestamp are skipped. The critical path goes
+// from the root to the leaf which became ready last (or is still pending).
+func NewReport(root *Resource, now time.Time) Report {
+	report := Report{GeneratedAt: now, Resources: []ReportEntry{}}
+
+	var last []ReportEntry
+	var lastAt time.Time
+
+	var visit func(r *Resource, path []ReportEntry)
+	visit = func(r *Resource, path []ReportEntry) {
+		ms := newMilestones(r, len(path))
+		if ms.Created.IsZero() {
+			report.Skipped++
+		} else {
+			e := newReportEntry(ms, now)
+			if r == root {
+				report.Root = &e
+			}
+			report.Resources = append(report.Resources, e)
+			path = append(path[:len(path):len(path)], e)
+		}
+
+		if len(r.Children) == 0 && len(path) > 0 {
+			at := now
+			if e := path[len(path)-1]; e.ReadyAt != nil {
+				at = *e.ReadyAt
+			}
+			if last == nil || at.After(lastAt) {
+				last, lastAt = path, at
+			}
+		}
+
+		for _, c := range r.Children {
+			visit(c, path)
+		}
+	}
+	if root != nil {
+		visit(root, nil)
+	}
+
+	if len(report.Resources) == 0 {
+		return report
+	}
+
+	report.CriticalPath = last
+
+	report.Slowest = append([]ReportEntry{}, report.Resources...)
+	sort.SliceStable(report.Slowest, func(i, j int) bool {
+		return report.Slowest[i].Duration > report.Slowest[j].Duration
+	})
+	report.Slowest = report.Slowest[:min(reportSlowest, len(report.Slowest))]
+
+	report.ByKind = aggregate(report.Resources, func(e ReportEntry) string {
+		if e.Group == "" {
+			return e.Kind
+		}
+		return e.Kind + "." + e.Group
+	})
+	report.ByGroup = aggregate(report.Resources, func(e ReportEntry) string {
+		if e.Group == "" {
+			return "core"
+		}
+		return e.Group
+	})
+
+	return report
+}
+
+func newReportEntry(ms Milestones, now time.Time) ReportEntry {
+	obj := ms.Resource.Unstructured
+	e := ReportEntry{
+		Kind:      obj.GetKind(),
+		Group:     obj.GroupVersionKind().Group,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Created:   ms.Created,
+		Duration:  now.Sub(ms.Created),
+	}
+	if at, ok := ms.IsReady(); ok {
+		e.Ready = true
+		e.ReadyAt = &at
+		e.Duration = at.Sub(ms.Created)
+	}
+	e.Seconds = e.Duration.Seconds()
+	return e
+}
+
+func aggregate(entries []ReportEntry, key func(e ReportEntry) string) []ReportAggregate {
+	byKey := map[string]*ReportAggregate{}
+	totals := map[string]time.Duration{}
+	for _, e := range entries {
+		k := key(e)
+		a, ok := byKey[k]
+		if !ok {
+			a = &ReportAggregate{Name: k}
+			byKey[k] = a
+		}
+		a.Count++
+		if e.Ready {
+			a.Ready++
+		}
+		a.Max = max(a.Max, e.Duration)
+		totals[k] += e.Duration
+	}
+
+	out := make([]ReportAggregate, 0, len(byKey))
+	for k, a := range byKey {
+		a.Average = totals[k] / time.Duration(a.Count)
+		a.AvgSecs = a.Average.Seconds()
+		a.MaxSecs = a.Max.Seconds()
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Max == out[j].Max {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Max > out[j].Max
+	})
+	return out
+}
+
+// String renders the report as plain text tables
+func (r Report) String() string {
+	if len(r.Resources) == 0 {
+		return "No timestamps found in the trace"
+	}
+
+	var b bytes.Buffer
+	pending := 0
+	for _, e := range r.Resources {
+		if !e.Ready {
+			pending++
+		}
+	}
+
+	// Without the root creation, the critical path is timed from its first
+	// resource, which is the closest ancestor with a timestamp
+	start := r.CriticalPath[0].Created
+	if r.Root != nil {
+		start = r.Root.Created
+		fmt.Fprintf(&b, "Provisioning report for %s/%s, generated at %s\n", r.Root.Kind, r.Root.Name, r.GeneratedAt.Format(time.RFC822))
+		fmt.Fprintf(&b, "Total: %s", formatReportDuration(*r.Root))
+	} else {
+		fmt.Fprintf(&b, "Provisioning report generated at %s\n", r.GeneratedAt.Format(time.RFC822))
+		b.WriteString("Total: unknown, the root has no creation timestamp")
+	}
+	if pending > 0 {
+		fmt.Fprintf(&b, " (%d of %d resources not ready yet)", pending, len(r.Resources))
+	}
+	if r.Skipped > 0 {
+		fmt.Fprintf(&b, " (%d resources without timestamps skipped)", r.Skipped)
+	}
+	b.WriteString("\n\n")
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "SLOWEST\tGROUP\tDURATION\tREADY")
+	for _, e := range r.Slowest {
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%t\n", e.Kind, e.Name, e.Group, formatReportDuration(e), e.Ready)
+	}
+	fmt.Fprintln(w)
+
+	for _, section := range []struct {
+		title string
+		aggs  []ReportAggregate
+	}{
+		{title: "KIND", aggs: r.ByKind},
+		{title: "GROUP", aggs: r.ByGroup},
+	} {
+		fmt.Fprintf(w, "%s\tCOUNT\tREADY\tAVERAGE\tMAX\n", section.title)
+		for _, a := range section.aggs {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", a.Name, a.Count, a.Ready, duration.HumanDuration(a.Average), duration.HumanDuration(a.Max))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "CRITICAL PATH\tCREATED\tREADY\tDURATION")
+	for i, e := range r.CriticalPath {
+		ready := "-"
+		if e.ReadyAt != nil {
+			ready = "+" + duration.HumanDuration(e.ReadyAt.Sub(start))
+		}
+		fmt.Fprintf(w, "%s%s/%s\t+%s\t%s\t%s\n",
+			strings.Repeat(" ", i), e.Kind, e.Name,
+			duration.HumanDuration(e.Created.Sub(start)),
+			ready,
+			formatReportDuration(e),
+		)
+	}
+	_ = w.Flush()
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatReportDuration marks durations of resources not ready yet as partial
+func formatReportDuration(e ReportEntry) string {
+	if e.Ready {
+		return duration.HumanDuration(e.Duration)
+	}
+	return duration.HumanDuration(e.Duration) + "…"
+}
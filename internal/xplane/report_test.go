@@ -0,0 +1,132 @@
+package xplane
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTimedResource(kind, name string, created, ready time.Duration, children ...*Resource) *Resource {
+	r := newComposedResource(kind, name, "", "", children...)
+	r.Unstructured.SetCreationTimestamp(metav1.NewTime(reportStart.Add(created)))
+	if ready > 0 {
+		setCondition(r, "Ready", "True", reportStart.Add(ready))
+	}
+	return r
+}
+
+func TestNewReport(t *testing.T) {
+	now := reportStart.Add(time.Hour)
+
+	type args struct {
+		trace *Resource
+	}
+
+	type want struct {
+		root         string
+		total        string
+		slowest      []string
+		criticalPath []string
+		byKind       map[string]int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllReady": {
+			reason: "Should follow the critical path to the leaf which became ready last",
+			args: args{
+				trace: newTimedResource("XBucket", "root", 0, 15*time.Minute,
+					newTimedResource("Bucket", "fast", time.Minute, 2*time.Minute),
+					newTimedResource("XNetwork", "network", time.Minute, 14*time.Minute,
+						newTimedResource("VPC", "vpc", 2*time.Minute, 12*time.Minute),
+						newTimedResource("Subnet", "subnet", 2*time.Minute, 5*time.Minute),
+					),
+				),
+			},
+			want: want{
+				root:         "root",
+				total:        "Total: 15m\n",
+				slowest:      []string{"root", "network", "vpc", "subnet", "fast"},
+				criticalPath: []string{"root", "network", "vpc"},
+				byKind:       map[string]int{"XBucket.example.org": 1, "Bucket.example.org": 1, "VPC.example.org": 1},
+			},
+		},
+		"Pending": {
+			reason: "Should follow the critical path to pending leaves, timing them until now",
+			args: args{
+				trace: newTimedResource("XBucket", "root", 0, 0,
+					newTimedResource("Bucket", "ready", time.Minute, 2*time.Minute),
+					newTimedResource("Bucket", "pending", 30*time.Minute, 0),
+				),
+			},
+			want: want{
+				root:         "root",
+				total:        "Total: 60m… (2 of 3 resources not ready yet)\n",
+				slowest:      []string{"root", "pending", "ready"},
+				criticalPath: []string{"root", "pending"},
+				byKind:       map[string]int{"Bucket.example.org": 2},
+			},
+		},
+		"RootWithoutTimestamp": {
+			reason: "Should not take a child for the root, when the root has no timestamp",
+			args: args{
+				trace: newComposedResource("XBucket", "root", "", "",
+					newTimedResource("Bucket", "first", time.Minute, 3*time.Minute),
+					newTimedResource("Bucket", "second", 2*time.Minute, 10*time.Minute),
+				),
+			},
+			want: want{
+				total:        "Total: unknown, the root has no creation timestamp (1 resources without timestamps skipped)\n",
+				slowest:      []string{"second", "first"},
+				criticalPath: []string{"second"},
+				byKind:       map[string]int{"Bucket.example.org": 2},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewReport(tc.args.trace, now)
+
+			root := ""
+			if got.Root != nil {
+				root = got.Root.Name
+			}
+			if root != tc.want.root {
+				t.Errorf("%s\nNewReport().Root = %q, want %q", tc.reason, root, tc.want.root)
+			}
+			if s := got.String(); !strings.Contains(s, tc.want.total) {
+				t.Errorf("%s\nNewReport().String() = %q, want it to contain %q", tc.reason, s, tc.want.total)
+			}
+
+			names := func(entries []ReportEntry) []string {
+				out := []string{}
+				for _, e := range entries {
+					out = append(out, e.Name)
+				}
+				return out
+			}
+			if s := names(got.Slowest); !slices.Equal(s, tc.want.slowest) {
+				t.Errorf("%s\nNewReport().Slowest = %v, want %v", tc.reason, s, tc.want.slowest)
+			}
+			if s := names(got.CriticalPath); !slices.Equal(s, tc.want.criticalPath) {
+				t.Errorf("%s\nNewReport().CriticalPath = %v, want %v", tc.reason, s, tc.want.criticalPath)
+			}
+			for kind, count := range tc.want.byKind {
+				found := false
+				for _, a := range got.ByKind {
+					if a.Name == kind {
+						found = a.Count == count
+					}
+				}
+				if !found {
+					t.Errorf("%s\nNewReport().ByKind = %+v, want %s with %d", tc.reason, got.ByKind, kind, count)
+				}
+			}
+		})
+	}
+}
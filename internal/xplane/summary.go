@@ -0,0 +1,74 @@
+package xplane
+
+import (
+	"sort"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Summary counts the resources of a trace by status and kind. Statuses
+// overlap, as a resource might be both not ready and deleting, for example.
+type Summary struct {
+	Total     int
+	Ready     int
+	NotReady  int
+	NotSynced int
+	Errors    int
+	Deleting  int
+	Paused    int
+	ByKind    []KindCount
+	Root      ResourceStatus
+}
+
+// KindCount is the number of resources of a kind
+type KindCount struct {
+	Kind  string
+	Count int
+}
+
+// NewSummary summarises a trace, with kinds sorted by count and then name
+func NewSummary(root *Resource) Summary {
+	s := Summary{Root: GetResourceStatus(root, root.Unstructured.GetName())}
+	kinds := map[string]int{}
+	s.add(root, kinds)
+
+	for kind, count := range kinds {
+		s.ByKind = append(s.ByKind, KindCount{Kind: kind, Count: count})
+	}
+	sort.Slice(s.ByKind, func(i, j int) bool {
+		if s.ByKind[i].Count != s.ByKind[j].Count {
+			return s.ByKind[i].Count > s.ByKind[j].Count
+		}
+		return s.ByKind[i].Kind < s.ByKind[j].Kind
+	})
+
+	return s
+}
+
+func (s *Summary) add(r *Resource, kinds map[string]int) {
+	s.Total++
+	kinds[r.Unstructured.GetKind()]++
+
+	if r.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+		s.Ready++
+	} else {
+		s.NotReady++
+	}
+	if r.GetCondition(xpv1.TypeSynced).Status == corev1.ConditionFalse {
+		s.NotSynced++
+	}
+	if r.Error != nil {
+		s.Errors++
+	}
+	if r.Unstructured.GetDeletionTimestamp() != nil {
+		s.Deleting++
+	}
+	if r.Unstructured.GetAnnotations()["crossplane.io/paused"] == "true" {
+		s.Paused++
+	}
+
+	for _, c := range r.Children {
+		s.add(c, kinds)
+	}
+}
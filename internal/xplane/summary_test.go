@@ -0,0 +1,88 @@
+package xplane
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	errv1 "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewSummary(t *testing.T) {
+	deleting := newComposedResource("Bucket", "deleting", "", "False")
+	deleting.Unstructured.SetDeletionTimestamp(&metav1.Time{Time: reportStart})
+	paused := newComposedResource("Bucket", "paused", "", "True")
+	paused.Unstructured.SetAnnotations(map[string]string{"crossplane.io/paused": "true"})
+	notSynced := newComposedResource("XBucket", "root", "", "False", newComposedResource("Policy", "policy", "", "True"))
+	setCondition(notSynced, "Synced", "False", time.Time{})
+	failed := newComposedResource("Bucket", "failed", "", "")
+	failed.Error = errv1.NewForbidden(schema.GroupResource{Resource: "buckets"}, "failed", errors.New("denied"))
+
+	type args struct {
+		trace *Resource
+	}
+
+	type want struct {
+		summary Summary
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Counts": {
+			reason: "Should count resources by status and kind, sorting kinds by count",
+			args: args{
+				trace: newComposedResource("XBucket", "root", "", "True",
+					newComposedResource("Policy", "policy", "", "True"),
+					deleting,
+					paused,
+				),
+			},
+			want: want{summary: Summary{
+				Total:    4,
+				Ready:    3,
+				NotReady: 1,
+				Deleting: 1,
+				Paused:   1,
+				ByKind:   []KindCount{{"Bucket", 2}, {"Policy", 1}, {"XBucket", 1}},
+				Root:     ResourceStatus{Name: "root", Ready: "True", Synced: "-"},
+			}},
+		},
+		"NotSynced": {
+			reason: "Should count resources which are not synced, reporting the root status",
+			args:   args{trace: notSynced},
+			want: want{summary: Summary{
+				Total:     2,
+				Ready:     1,
+				NotReady:  1,
+				NotSynced: 1,
+				ByKind:    []KindCount{{"Policy", 1}, {"XBucket", 1}},
+				Root:      ResourceStatus{Name: "root", Ready: "False", Synced: "False"},
+			}},
+		},
+		"Errors": {
+			reason: "Should count resources which could not be fetched",
+			args:   args{trace: newComposedResource("XBucket", "root", "", "True", failed)},
+			want: want{summary: Summary{
+				Total:    2,
+				Ready:    1,
+				NotReady: 1,
+				Errors:   1,
+				ByKind:   []KindCount{{"Bucket", 1}, {"XBucket", 1}},
+				Root:     ResourceStatus{Name: "root", Ready: "True", Synced: "-"},
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewSummary(tc.args.trace)
+			if !reflect.DeepEqual(got, tc.want.summary) {
+				t.Errorf("%s\nNewSummary() = %+v, want %+v", tc.reason, got, tc.want.summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package xplane
+
+import (
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Milestones are the points in time in which a resource changed its state.
+// Synced and Ready hold the last transition of each condition.
+type Milestones struct {
+	Resource *Resource
+	Depth    int
+	Created  time.Time
+	Deleted  time.Time
+	Synced   xpv1.Condition
+	Ready    xpv1.Condition
+}
+
+// IsReady reports whether the resource is ready, along with when it became so
+func (m Milestones) IsReady() (time.Time, bool) {
+	if m.Ready.Status != "True" || m.Ready.LastTransitionTime.IsZero() {
+		return time.Time{}, false
+	}
+	return m.Ready.LastTransitionTime.Time, true
+}
+
+// GetMilestones returns the milestones of all resources in the trace, in
+// depth-first order
+func GetMilestones(root *Resource) []Milestones {
+	out := []Milestones{}
+
+	var visit func(r *Resource, depth int)
+	visit = func(r *Resource, depth int) {
+		out = append(out, newMilestones(r, depth))
+
+		for _, c := range r.Children {
+			visit(c, depth+1)
+		}
+	}
+	if root != nil {
+		visit(root, 0)
+	}
+
+	return out
+}
+
+func newMilestones(r *Resource, depth int) Milestones {
+	ms := Milestones{
+		Resource: r,
+		Depth:    depth,
+		Created:  r.Unstructured.GetCreationTimestamp().Time,
+		Synced:   r.GetCondition(xpv1.TypeSynced),
+		Ready:    r.GetCondition(xpv1.TypeReady),
+	}
+	if d := r.Unstructured.GetDeletionTimestamp(); d != nil {
+		ms.Deleted = d.Time
+	}
+	return ms
+}
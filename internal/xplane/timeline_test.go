@@ -0,0 +1,109 @@
+package xplane
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	// milestone keeps what matters of Milestones, which also hold the resource
+	type milestone struct {
+		name    string
+		depth   int
+		created time.Time
+		synced  string
+		ready   string
+		readyAt time.Time
+	}
+
+	type args struct {
+		trace *Resource
+	}
+
+	type want struct {
+		milestones []milestone
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Empty": {
+			reason: "Should return no milestones without a trace",
+			args:   args{},
+			want:   want{milestones: []milestone{}},
+		},
+		"Order": {
+			reason: "Should return milestones in depth-first order, along with their depth",
+			args: args{
+				trace: newTimedResource("XBucket", "root", 0, 15*time.Minute,
+					newTimedResource("XNetwork", "network", time.Minute, 14*time.Minute,
+						newTimedResource("VPC", "vpc", 2*time.Minute, 12*time.Minute),
+					),
+					newTimedResource("Bucket", "bucket", time.Minute, 2*time.Minute),
+				),
+			},
+			want: want{milestones: []milestone{
+				{name: "root", depth: 0, created: reportStart, ready: "True", readyAt: reportStart.Add(15 * time.Minute)},
+				{name: "network", depth: 1, created: reportStart.Add(time.Minute), ready: "True", readyAt: reportStart.Add(14 * time.Minute)},
+				{name: "vpc", depth: 2, created: reportStart.Add(2 * time.Minute), ready: "True", readyAt: reportStart.Add(12 * time.Minute)},
+				{name: "bucket", depth: 1, created: reportStart.Add(time.Minute), ready: "True", readyAt: reportStart.Add(2 * time.Minute)},
+			}},
+		},
+		"Conditions": {
+			reason: "Should keep the last transition of each condition, but only take True Ready ones as ready",
+			args: args{
+				trace: func() *Resource {
+					r := newTimedResource("Bucket", "bucket", 0, 0)
+					setCondition(r, "Synced", "True", reportStart.Add(time.Minute))
+					setCondition(r, "Ready", "False", reportStart.Add(2*time.Minute))
+					return r
+				}(),
+			},
+			want: want{milestones: []milestone{
+				{name: "bucket", created: reportStart, synced: "True", ready: "False"},
+			}},
+		},
+		"MissingConditions": {
+			reason: "Should leave conditions which are not set empty",
+			args:   args{trace: newTimedResource("Bucket", "bucket", 0, 0)},
+			want: want{milestones: []milestone{
+				{name: "bucket", created: reportStart},
+			}},
+		},
+		"MissingTimestamps": {
+			reason: "Should keep resources without timestamps, with zero times",
+			args: args{
+				trace: newComposedResource("XBucket", "root", "", "True",
+					newTimedResource("Bucket", "bucket", time.Minute, 0),
+				),
+			},
+			want: want{milestones: []milestone{
+				{name: "root", ready: "True"},
+				{name: "bucket", depth: 1, created: reportStart.Add(time.Minute)},
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := []milestone{}
+			for _, ms := range GetMilestones(tc.args.trace) {
+				readyAt, _ := ms.IsReady()
+				// Timestamps are parsed in the local time zone
+				got = append(got, milestone{
+					name:    ms.Resource.Unstructured.GetName(),
+					depth:   ms.Depth,
+					created: ms.Created.UTC(),
+					synced:  string(ms.Synced.Status),
+					ready:   string(ms.Ready.Status),
+					readyAt: readyAt.UTC(),
+				})
+			}
+
+			if !reflect.DeepEqual(got, tc.want.milestones) {
+				t.Errorf("%s\nGetMilestones() = %+v, want %+v", tc.reason, got, tc.want.milestones)
+			}
+		})
+	}
+}